@@ -6,50 +6,198 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
+	"time"
 
 	"cron-microservice/internal/config"
+	"cron-microservice/internal/logging"
 	"cron-microservice/internal/scheduler"
 	"cron-microservice/internal/server"
+	"cron-microservice/internal/version"
 )
 
 func main() {
 	var (
-		configFile = flag.String("config", "config.yaml", "Path to configuration file")
-		addr       = flag.String("addr", ":8080", "HTTP server address")
+		configFile        = flag.String("config", "config.yaml", "Path to configuration file")
+		addr              = flag.String("addr", ":8080", "HTTP server address")
+		logFile           = flag.String("log-file", "", "Write logs to this file instead of stderr")
+		logMaxSize        = flag.Int("log-max-size", 100, "Rotate the log file after this many megabytes (0 disables rotation)")
+		logBackups        = flag.Int("log-max-backups", 5, "Number of rotated log files to keep")
+		reminderWindow    = flag.Duration("reminder-window", 24*time.Hour, "Only arm timers for reminders due within this window (0 arms all)")
+		maxReminderTimers = flag.Int("max-reminder-timers", 10000, "Maximum number of armed reminder timers (0 for no limit)")
+		reminderJitter    = flag.Duration("reminder-jitter", 0, "Delay each reminder by a random amount up to this long past its datetime, to spread out reminders due together (0 disables)")
+		maxReminderLead   = flag.Duration("max-reminder-lead-time", 90*24*time.Hour, "Reject reminders saved further ahead than this (0 for no limit)")
+		warmup            = flag.Int("warmup-concurrency", 0, "Preconnect to every job's hosts at startup with this many concurrent requests (0 disables)")
+		debounce          = flag.Duration("config-change-debounce", 2*time.Second, "Coalesce config changes within this window into one on_config_change notification")
+		maxManualRuns     = flag.Int("max-manual-runs", 4, "Maximum test, invoke and rerun-secondary runs in progress at once; more get 429 (0 for no limit)")
+		sloWindow         = flag.Duration("slo-window", time.Hour, "Rolling window for per-job success ratios in /metrics and the slo endpoint")
+		maxVariableSize   = flag.Int("max-variable-size", 1<<20, "Cap in bytes on values extracted by jq selectors (0 for no limit)")
+		noContentType     = flag.Bool("no-default-content-type", false, "Don't detect a Content-Type for request bodies; send one only when a webhook configures it")
+		maxRuntime        = flag.Duration("max-runtime", 0, "Shut down gracefully and exit 0 after running this long (0 runs until signalled)")
+		shutdownTimeout   = flag.Duration("shutdown-timeout", 30*time.Second, "How long shutdown waits for in-flight executions to finish")
+		shutdownNotify    = flag.Duration("shutdown-notify-timeout", 5*time.Second, "How long shutdown waits for the on_shutdown webhook")
+		hashFile          = flag.String("response-hash-file", "", "Where notify_on_change jobs persist response hashes (default: <config>.hashes.json)")
+		prevVarsFile      = flag.String("prev-vars-file", "", "Where variables extracted on each job's last successful run are persisted (default: <config>.prev.json)")
+		monitorStateFile  = flag.String("monitor-state-file", "", "Where monitor jobs persist whether they are down (default: <config>.monitors.json)")
+		timezone          = flag.String("timezone", "Local", "Time zone for cron schedules and for reminder datetimes written without one, e.g. Europe/Berlin")
+		readOnly          = flag.Bool("read-only", false, "Serve the configuration read-only, rejecting API changes")
+		basePath          = flag.String("base-path", "", "Serve the UI and API under this path prefix, e.g. /cron, for path-based reverse proxies")
+		templatesDir      = flag.String("templates-dir", "", "Development: serve the UI templates from this directory, re-read on every page load, instead of the embedded ones")
+		maxJobs           = flag.Int("max-jobs", 0, "Maximum number of jobs that can be configured (0 for no limit)")
+		allowHosts        = flag.String("allow-hosts", "", "Comma-separated hosts, *.domain wildcards, IPs or CIDR ranges webhooks may call (empty allows all)")
+		denyHosts         = flag.String("deny-hosts", "", "Comma-separated hosts, *.domain wildcards, IPs or CIDR ranges webhooks may never call")
+		allowLinkLocal    = flag.Bool("allow-link-local", false, "Allow webhooks to call link-local and cloud metadata addresses")
+		startupSplay      = flag.Duration("startup-splay", 0, "Delay scheduling jobs by a random amount up to this long at startup (0 loads immediately)")
+		showVersion       = flag.Bool("version", false, "Print the version, commit, build date and Go version, then exit")
 	)
 	flag.Parse()
 
+	if *showVersion {
+		fmt.Println("cron-microservice " + version.Get().String())
+		return
+	}
+
+	// Configure log output
+	if *logFile != "" {
+		logWriter, err := logging.NewRotatingFile(*logFile, *logMaxSize, *logBackups)
+		if err != nil {
+			log.Fatalf("Failed to open log file: %v", err)
+		}
+		defer logWriter.Close()
+		log.SetOutput(logWriter)
+	}
+
+	// Cron schedules run, and reminder datetimes without a zone are read,
+	// in this location
+	loc, err := time.LoadLocation(*timezone)
+	if err != nil {
+		log.Fatalf("Invalid timezone: %v", err)
+	}
+	config.SetDefaultLocation(loc)
+	log.Printf("Using time zone %s (%s)", loc, time.Now().In(loc).Format("MST, UTC-07:00"))
+
 	// Load configuration
 	cfg := config.New(*configFile)
 	if err := cfg.Load(); err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Refuse changes up front rather than failing at save time
+	if *readOnly {
+		cfg.SetReadOnly(true)
+	} else if err := cfg.CheckWritable(); err != nil {
+		log.Printf("Warning: Configuration file is not writable, running read-only: %v", err)
+		cfg.SetReadOnly(true)
+	}
+	cfg.SetMaxJobs(*maxJobs)
+	cfg.SetMaxReminderLeadTime(*maxReminderLead)
+
+	// Restrict which hosts webhooks may call
+	hostPolicy, err := scheduler.NewHostPolicy(splitList(*allowHosts), splitList(*denyHosts), *allowLinkLocal)
+	if err != nil {
+		log.Fatalf("Invalid host policy: %v", err)
+	}
+
 	// Create and start scheduler
 	sched := scheduler.New(cfg)
+	sched.SetHostPolicy(hostPolicy)
+	sched.SetReminderWindow(*reminderWindow, *maxReminderTimers)
+	sched.SetReminderJitter(*reminderJitter)
+	sched.SetWarmup(*warmup)
+	sched.SetMaxManualRuns(*maxManualRuns)
+	sched.SetSLOWindow(*sloWindow)
+	sched.SetMaxVariableSize(*maxVariableSize)
+	sched.SetDefaultContentType(!*noContentType)
+	if *hashFile == "" {
+		*hashFile = strings.TrimSuffix(*configFile, filepath.Ext(*configFile)) + ".hashes.json"
+	}
+	if err := sched.SetResponseHashFile(*hashFile); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	if *prevVarsFile == "" {
+		*prevVarsFile = strings.TrimSuffix(*configFile, filepath.Ext(*configFile)) + ".prev.json"
+	}
+	if err := sched.SetPreviousVariablesFile(*prevVarsFile); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	if *monitorStateFile == "" {
+		*monitorStateFile = strings.TrimSuffix(*configFile, filepath.Ext(*configFile)) + ".monitors.json"
+	}
+	if err := sched.SetMonitorStateFile(*monitorStateFile); err != nil {
+		log.Printf("Warning: %v", err)
+	}
 	sched.Start()
-	defer sched.Stop()
 
-	// Load existing jobs
-	if err := sched.LoadJobs(); err != nil {
-		log.Printf("Warning: Failed to load some jobs: %v", err)
+	// Notify the on_config_change webhook when jobs change
+	sched.WatchConfigChanges(*debounce)
+
+	// Load existing jobs, in the background when splaying startup so the
+	// API is served and signals are handled during the delay
+	loadJobs := func() {
+		if err := sched.LoadJobsAfterSplay(*startupSplay); err != nil {
+			log.Printf("Warning: Failed to load some jobs: %v", err)
+		}
+	}
+	if *startupSplay > 0 {
+		go loadJobs()
+	} else {
+		loadJobs()
 	}
 
 	// Create and start HTTP server
 	srv := server.New(cfg, sched)
+	srv.SetBasePath(*basePath)
+	if *templatesDir != "" {
+		if err := srv.SetTemplateDir(*templatesDir); err != nil {
+			log.Fatalf("Invalid templates directory: %v", err)
+		}
+		log.Printf("Serving UI templates from %s, reloaded on every page load", *templatesDir)
+	}
 
 	// Handle graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
-		log.Printf("Starting cron microservice on %s", *addr)
+		log.Printf("Starting cron microservice %s on %s", version.Get(), *addr)
 		if err := srv.Start(*addr); err != nil {
 			log.Fatalf("Server failed: %v", err)
 		}
 	}()
 
-	<-sigChan
-	fmt.Println("\nShutting down gracefully...")
-}
\ No newline at end of file
+	// Run until signalled or, when bounded, until the maximum runtime
+	var deadline <-chan time.Time
+	if *maxRuntime > 0 {
+		deadline = time.After(*maxRuntime)
+	}
+
+	var reason string
+	select {
+	case sig := <-sigChan:
+		fmt.Println("\nShutting down gracefully...")
+		reason = sig.String()
+	case <-deadline:
+		log.Printf("Maximum runtime of %v reached, shutting down gracefully", *maxRuntime)
+		reason = "max runtime reached"
+	}
+
+	sched.NotifyShutdown(reason, *shutdownNotify)
+
+	if !sched.Shutdown(*shutdownTimeout) {
+		log.Printf("Warning: In-flight executions still running after %v, exiting anyway", *shutdownTimeout)
+	}
+	log.Printf("Shutdown complete")
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -1,13 +1,12 @@
 module cron-microservice
 
-go 1.24
+go 1.24.0
 
 require (
+	github.com/itchyny/gojq v0.12.17
 	github.com/robfig/cron/v3 v3.0.1
+	golang.org/x/text v0.32.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
-require (
-	github.com/itchyny/gojq v0.12.17 // indirect
-	github.com/itchyny/timefmt-go v0.1.6 // indirect
-)
+require github.com/itchyny/timefmt-go v0.1.6 // indirect
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AfterOffset returns how long after its AfterJob fires a chained job runs.
+// An unset offset is 0, running the job alongside the other.
+func (j CronJob) AfterOffset() (time.Duration, error) {
+	if j.Offset == "" {
+		return 0, nil
+	}
+	offset, err := time.ParseDuration(j.Offset)
+	if err != nil {
+		return 0, fmt.Errorf("invalid offset %q: %w", j.Offset, err)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("offset %q must not be negative", j.Offset)
+	}
+	return offset, nil
+}
+
+// ValidateAfterJob returns an error when a chained job follows itself or
+// has an offset that can't be parsed. An offset without after_job is an
+// error too, as it would be silently ignored.
+func (j CronJob) ValidateAfterJob() error {
+	if j.AfterJob == "" {
+		if j.Offset != "" {
+			return fmt.Errorf("offset %q is set without after_job", j.Offset)
+		}
+		return nil
+	}
+	if j.AfterJob == j.ID {
+		return fmt.Errorf("job %s cannot run after itself", j.ID)
+	}
+	_, err := j.AfterOffset()
+	return err
+}
+
+// ValidateAfterJobChain returns an error when a chained job's after_job is
+// not one of jobs, or when following after_job from the job comes back
+// around, as either would leave the job without anything to run it. jobs
+// are the jobs the chained job is saved alongside; an earlier version of
+// the job among them is ignored.
+func (j CronJob) ValidateAfterJobChain(jobs []CronJob) error {
+	if j.AfterJob == "" {
+		return nil
+	}
+
+	byID := make(map[string]CronJob, len(jobs)+1)
+	for _, job := range jobs {
+		byID[job.ID] = job
+	}
+	byID[j.ID] = j
+
+	if _, ok := byID[j.AfterJob]; !ok {
+		return fmt.Errorf("job %s runs after job %s, which does not exist", j.ID, j.AfterJob)
+	}
+
+	chain := []string{j.ID}
+	seen := map[string]bool{j.ID: true}
+	for id := j.AfterJob; id != ""; id = byID[id].AfterJob {
+		chain = append(chain, id)
+		if seen[id] {
+			return fmt.Errorf("job chain %s is a cycle", strings.Join(chain, " -> "))
+		}
+		seen[id] = true
+		if _, ok := byID[id]; !ok {
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Change summarizes which jobs differ between two saved configurations
+type Change struct {
+	Added   []string `json:"added"`
+	Updated []string `json:"updated"`
+	Removed []string `json:"removed"`
+}
+
+// Empty reports whether the change contains no job differences
+func (ch Change) Empty() bool {
+	return len(ch.Added) == 0 && len(ch.Updated) == 0 && len(ch.Removed) == 0
+}
+
+// OnChange registers a function called after every successful Save that
+// changed at least one job. It runs synchronously on the saving goroutine,
+// so it must not block.
+func (c *Config) OnChange(listener func(Change)) {
+	c.saveMu.Lock()
+	defer c.saveMu.Unlock()
+
+	c.listener = listener
+}
+
+// snapshotJobs indexes jobs by ID for later comparison
+func snapshotJobs(jobs []CronJob) map[string]CronJob {
+	snapshot := make(map[string]CronJob, len(jobs))
+	for _, job := range jobs {
+		snapshot[job.ID] = job
+	}
+	return snapshot
+}
+
+// diffJobs compares two snapshots and returns the sorted job IDs that were
+// added, updated, or removed
+func diffJobs(before, after map[string]CronJob) Change {
+	change := Change{Added: []string{}, Updated: []string{}, Removed: []string{}}
+
+	for id, job := range after {
+		previous, existed := before[id]
+		switch {
+		case !existed:
+			change.Added = append(change.Added, id)
+		case !reflect.DeepEqual(previous, job):
+			change.Updated = append(change.Updated, id)
+		}
+	}
+
+	for id := range before {
+		if _, exists := after[id]; !exists {
+			change.Removed = append(change.Removed, id)
+		}
+	}
+
+	sort.Strings(change.Added)
+	sort.Strings(change.Updated)
+	sort.Strings(change.Removed)
+	return change
+}
@@ -1,48 +1,158 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 type WebhookConfig struct {
-	URL                  string            `yaml:"url" json:"url"`
-	Method               string            `yaml:"method" json:"method"`
-	Headers              map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
-	Body                 string            `yaml:"body,omitempty" json:"body,omitempty"`
-	JQSelectors          map[string]string `yaml:"jq_selectors,omitempty" json:"jq_selectors,omitempty"`
-	BodyTemplate         string            `yaml:"body_template,omitempty" json:"body_template,omitempty"`
-	OnlyIfVarsNonEmpty   bool              `yaml:"only_if_vars_non_empty,omitempty" json:"only_if_vars_non_empty,omitempty"`
-	Timeout              int               `yaml:"timeout,omitempty" json:"timeout,omitempty"` // Timeout in seconds, 0 means use default
-	Enabled              bool              `yaml:"enabled" json:"enabled"`                     // Enable/disable webhook
+	URL                string            `yaml:"url" json:"url"`
+	URLs               []string          `yaml:"urls,omitempty" json:"urls,omitempty"`       // Rotated by weighted round-robin when set
+	Weights            []int             `yaml:"weights,omitempty" json:"weights,omitempty"` // Weights matching URLs by index, default 1
+	Method             string            `yaml:"method" json:"method"`
+	Headers            map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	QueryParams        map[string]string `yaml:"query_params,omitempty" json:"query_params,omitempty"` // Appended to the URL; a lone {{array}} placeholder repeats the parameter per element
+	Body               string            `yaml:"body,omitempty" json:"body,omitempty"`
+	JQSelectors        map[string]string `yaml:"jq_selectors,omitempty" json:"jq_selectors,omitempty"`
+	ComputedVars       map[string]string `yaml:"computed_vars,omitempty" json:"computed_vars,omitempty"` // Variables derived by jq programs run over all other variables
+	BodyTemplate       string            `yaml:"body_template,omitempty" json:"body_template,omitempty"`
+	OnlyIfVarsNonEmpty bool              `yaml:"only_if_vars_non_empty,omitempty" json:"only_if_vars_non_empty,omitempty"`
+	Timeout            int               `yaml:"timeout,omitempty" json:"timeout,omitempty"`                           // Timeout in seconds, 0 means use default
+	ConnectTimeout     int               `yaml:"connect_timeout,omitempty" json:"connect_timeout,omitempty"`           // Seconds allowed to connect and complete the TLS handshake, 0 means 30
+	ContentType        string            `yaml:"content_type,omitempty" json:"content_type,omitempty"`                 // Content-Type for the body when no header is set, detected if empty
+	AllowGetBody       bool              `yaml:"allow_get_body,omitempty" json:"allow_get_body,omitempty"`             // Send Body with GET and HEAD requests instead of dropping it
+	ResponseCharset    string            `yaml:"response_charset,omitempty" json:"response_charset,omitempty"`         // Charset the response is transcoded from, such as ISO-8859-1; UTF-8 if empty
+	Retries            int               `yaml:"retries,omitempty" json:"retries,omitempty"`                           // Extra attempts on transport errors, 429 and 5xx
+	RetryBackoff       int               `yaml:"retry_backoff,omitempty" json:"retry_backoff,omitempty"`               // Initial backoff in milliseconds, doubled per retry, 0 means 1s
+	RetryIfJQ          string            `yaml:"retry_if_jq,omitempty" json:"retry_if_jq,omitempty"`                   // Retry a successful JSON response when this jq condition is truthy
+	RetryJitter        string            `yaml:"retry_jitter,omitempty" json:"retry_jitter,omitempty"`                 // "none" (default), "full" or "equal"
+	RetryOnDNS         bool              `yaml:"retry_on_dns,omitempty" json:"retry_on_dns,omitempty"`                 // Also retry names that don't exist (NXDOMAIN); transient DNS failures are always retried
+	ResolveTo          map[string]string `yaml:"resolve_to,omitempty" json:"resolve_to,omitempty"`                     // host:port -> ip:port dial overrides, like curl --resolve
+	HTTP2              bool              `yaml:"http2,omitempty" json:"http2,omitempty"`                               // Require HTTP/2, using h2c prior knowledge for http:// URLs
+	ExpectJQ           string            `yaml:"expect_jq,omitempty" json:"expect_jq,omitempty"`                       // jq condition the response must satisfy, used by prechecks
+	DropEmptyHeaders   bool              `yaml:"drop_empty_headers,omitempty" json:"drop_empty_headers,omitempty"`     // Omit headers whose template variables are missing or empty
+	TemplateMode       string            `yaml:"template_mode,omitempty" json:"template_mode,omitempty"`               // "string" (default) or "json" for structured body templates
+	ForEach            string            `yaml:"for_each,omitempty" json:"for_each,omitempty"`                         // jq selector for an array; a secondary is sent once per element as {{item}}
+	ForEachLimit       int               `yaml:"for_each_limit,omitempty" json:"for_each_limit,omitempty"`             // Most items sent for, 0 means 100
+	ForEachConcurrency int               `yaml:"for_each_concurrency,omitempty" json:"for_each_concurrency,omitempty"` // Per-item calls in flight at once, 0 means 4
+	OutputTransform    string            `yaml:"output_transform,omitempty" json:"output_transform,omitempty"`         // jq expression reshaping the primary's response before it is saved with save_output
+	Enabled            bool              `yaml:"enabled" json:"enabled"`                                               // Enable/disable webhook
 }
 
 type Reminder struct {
-	ID          string    `yaml:"id" json:"id"`
-	Text        string    `yaml:"text" json:"text"`
-	Datetime    time.Time `yaml:"datetime" json:"datetime"`
+	ID         string         `yaml:"id" json:"id"`
+	Text       string         `yaml:"text" json:"text"`
+	Datetime   time.Time      `yaml:"datetime" json:"datetime"`
+	Webhook    *WebhookConfig `yaml:"webhook,omitempty" json:"webhook,omitempty"`         // Overrides the job's primary webhook for this reminder
+	Enabled    *bool          `yaml:"enabled,omitempty" json:"enabled,omitempty"`         // Defaults to true when unset
+	LastStatus string         `yaml:"last_status,omitempty" json:"last_status,omitempty"` // "failure" once a run failed; reminders that succeed are deleted
+	LastError  string         `yaml:"last_error,omitempty" json:"last_error,omitempty"`   // Error of the last failed run
+	LastRunAt  *time.Time     `yaml:"last_run_at,omitempty" json:"last_run_at,omitempty"` // When the reminder last ran
+	FailedStep string         `yaml:"failed_step,omitempty" json:"failed_step,omitempty"` // "primary" or "secondary", the step of the last failed run
+}
+
+// Steps of a reminder run, recorded as a failed reminder's FailedStep
+const (
+	ReminderStepPrimary   = "primary"
+	ReminderStepSecondary = "secondary"
+)
+
+// ReminderRun is the outcome of a reminder's run, recorded on the reminder
+// by the scheduler
+type ReminderRun struct {
+	Status     string
+	Error      string
+	FailedStep string
+	At         time.Time
+}
+
+// KeepReminderRunState replaces the run state of the job's reminders, which
+// only the scheduler records, with that of the stored reminders of the same
+// ID, so clients can neither set nor clear it
+func (j *CronJob) KeepReminderRunState(stored []Reminder) {
+	reminders := make([]Reminder, len(j.Reminders))
+	for i, reminder := range j.Reminders {
+		reminder.LastStatus, reminder.LastError, reminder.LastRunAt = "", "", nil
+		reminder.FailedStep = ""
+		for _, s := range stored {
+			if s.ID == reminder.ID {
+				reminder.LastStatus, reminder.LastError, reminder.LastRunAt = s.LastStatus, s.LastError, s.LastRunAt
+				reminder.FailedStep = s.FailedStep
+				break
+			}
+		}
+		reminders[i] = reminder
+	}
+	if j.Reminders != nil {
+		j.Reminders = reminders
+	}
+}
+
+// IsEnabled reports whether the reminder should fire. Reminders without an
+// explicit enabled flag are enabled.
+func (r Reminder) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
 }
 
 type CronJob struct {
-	ID          string         `yaml:"id" json:"id"`
-	Name        string         `yaml:"name" json:"name"`
-	Schedule    string         `yaml:"schedule" json:"schedule"`
-	Enabled     bool           `yaml:"enabled" json:"enabled"`
-	Primary     WebhookConfig  `yaml:"primary" json:"primary"`
-	Secondary   *WebhookConfig `yaml:"secondary,omitempty" json:"secondary,omitempty"`
-	SaveOutput  bool           `yaml:"save_output,omitempty" json:"save_output,omitempty"`
-	Description string         `yaml:"description,omitempty" json:"description,omitempty"`
-	Reminders   []Reminder     `yaml:"reminders,omitempty" json:"reminders,omitempty"`
+	ID                     string         `yaml:"id" json:"id"`
+	Name                   string         `yaml:"name" json:"name"`
+	Schedule               string         `yaml:"schedule" json:"schedule"`
+	Interval               string         `yaml:"interval,omitempty" json:"interval,omitempty"`   // Human-friendly alternative to Schedule, e.g. "every 5 minutes"
+	AfterJob               string         `yaml:"after_job,omitempty" json:"after_job,omitempty"` // Run Offset after this job's schedule fires, instead of on a schedule of its own
+	Offset                 string         `yaml:"offset,omitempty" json:"offset,omitempty"`       // Delay after the AfterJob fires, such as "5m"
+	Enabled                bool           `yaml:"enabled" json:"enabled"`
+	Draft                  bool           `yaml:"draft,omitempty" json:"draft,omitempty"`                         // Work in progress, never scheduled regardless of Enabled
+	Template               bool           `yaml:"template,omitempty" json:"template,omitempty"`                   // Never scheduled; run on demand through the invoke endpoint
+	Precheck               *WebhookConfig `yaml:"precheck,omitempty" json:"precheck,omitempty"`                   // Runs first; the job is skipped unless its ExpectJQ is truthy
+	EnabledCheck           *WebhookConfig `yaml:"enabled_check,omitempty" json:"enabled_check,omitempty"`         // Feature flag asked before scheduled ticks; ticks are skipped unless its ExpectJQ is truthy
+	EnabledCheckTTL        int            `yaml:"enabled_check_ttl,omitempty" json:"enabled_check_ttl,omitempty"` // Seconds an enabled check answer is reused, 0 means 60
+	Primary                WebhookConfig  `yaml:"primary" json:"primary"`
+	Secondary              *WebhookConfig `yaml:"secondary,omitempty" json:"secondary,omitempty"`
+	OnPrimaryFailure       *WebhookConfig `yaml:"on_primary_failure,omitempty" json:"on_primary_failure,omitempty"`   // Runs instead of Secondary when the primary fails
+	NotifyOnChange         bool           `yaml:"notify_on_change,omitempty" json:"notify_on_change,omitempty"`       // Continue past the primary only when its response changed
+	SensitiveVariables     []string       `yaml:"sensitive_variables,omitempty" json:"sensitive_variables,omitempty"` // Variables whose values are masked in the run's logs
+	OutputsFrom            []string       `yaml:"outputs_from,omitempty" json:"outputs_from,omitempty"`               // Jobs whose latest saved outputs are available as {{outputs.<id>}}
+	StreamToSecondary      bool           `yaml:"stream_to_secondary,omitempty" json:"stream_to_secondary,omitempty"` // Pipe the primary's response body straight into the secondary's request, bypassing jq, templates and saved output
+	Monitor                bool           `yaml:"monitor,omitempty" json:"monitor,omitempty"`                         // Health probe: only the primary is requested, and OnPrimaryFailure fires when it goes down
+	SaveOutput             bool           `yaml:"save_output,omitempty" json:"save_output,omitempty"`
+	PrettyOutput           bool           `yaml:"pretty_output,omitempty" json:"pretty_output,omitempty"` // Re-indent JSON when the saved output is viewed
+	Description            string         `yaml:"description,omitempty" json:"description,omitempty"`
+	Tags                   []string       `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Order                  int            `yaml:"order,omitempty" json:"order,omitempty"`                                       // Display position, lower first; 0 sorts after ordered jobs
+	Coalesce               string         `yaml:"coalesce,omitempty" json:"coalesce,omitempty"`                                 // Tick policy while a run is in progress: "queue" (default), "skip" or "replace"
+	SampleRate             *float64       `yaml:"sample_rate,omitempty" json:"sample_rate,omitempty"`                           // Fraction of scheduled ticks that run, 0.0-1.0; unset runs every tick
+	MaxLateness            string         `yaml:"max_lateness,omitempty" json:"max_lateness,omitempty"`                         // Skip a scheduled tick that starts more than this long after it was due, such as "30s"
+	MaxConsecutiveFailures int            `yaml:"max_consecutive_failures,omitempty" json:"max_consecutive_failures,omitempty"` // Disable the job once exceeded, 0 means never
+	OnAutoDisable          *WebhookConfig `yaml:"on_auto_disable,omitempty" json:"on_auto_disable,omitempty"`                   // Alerted when the job is auto-disabled
+	DisabledReason         string         `yaml:"disabled_reason,omitempty" json:"disabled_reason,omitempty"`                   // Set when the scheduler disabled the job
+	SuspendedUntil         *time.Time     `yaml:"suspended_until,omitempty" json:"suspended_until,omitempty"`                   // Scheduled ticks are skipped until then; cleared once it passes
+	QuietHours             *QuietHours    `yaml:"quiet_hours,omitempty" json:"quiet_hours,omitempty"`                           // Daily window in which scheduled ticks are skipped
+	ReminderPolicy         string         `yaml:"reminder_policy,omitempty" json:"reminder_policy,omitempty"`                   // Whether reminders follow the job's enabled state and suspension: "independent" (default) or "cascade"
+	DefaultBodyTemplate    string         `yaml:"default_body_template,omitempty" json:"default_body_template,omitempty"`       // Body template for the job's webhooks and reminders that set neither body nor body_template
+	Reminders              []Reminder     `yaml:"reminders,omitempty" json:"reminders,omitempty"`
 }
 
 type Config struct {
-	mu       sync.RWMutex
-	filename string
-	Jobs     []CronJob `yaml:"jobs"`
+	mu             sync.RWMutex
+	filename       string
+	saveMu         sync.Mutex         // Serializes saves and guards saved and listener
+	saved          map[string]CronJob // Jobs as of the last load or save, for change detection
+	listener       func(Change)       // Called after a save that changed jobs
+	readOnly       atomic.Bool        // When set, Save refuses to write
+	maxJobs        atomic.Int64       // Cap on the number of jobs, 0 means none
+	maxLeadTime    atomic.Int64       // Furthest ahead a reminder may be saved, 0 means no limit
+	OnConfigChange *WebhookConfig     `yaml:"on_config_change,omitempty"` // Notified (debounced) when jobs change
+	OnShutdown     *WebhookConfig     `yaml:"on_shutdown,omitempty"`      // Notified during graceful shutdown
+	Jobs           []CronJob          `yaml:"jobs"`
 }
 
 func New(filename string) *Config {
@@ -53,6 +163,9 @@ func New(filename string) *Config {
 }
 
 func (c *Config) Load() error {
+	// saveMu is taken before mu, in the same order as Save
+	c.saveMu.Lock()
+	defer c.saveMu.Unlock()
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -69,14 +182,57 @@ func (c *Config) Load() error {
 		return fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	c.saved = snapshotJobs(c.Jobs)
+
 	return nil
 }
 
+// ErrReadOnly is returned by Save when the config is read-only
+var ErrReadOnly = errors.New("configuration is read-only")
+
+// SetReadOnly marks the config as read-only. Callers should refuse changes
+// up front; Save fails with ErrReadOnly as a last line of defence.
+func (c *Config) SetReadOnly(readOnly bool) {
+	c.readOnly.Store(readOnly)
+}
+
+// IsReadOnly reports whether the config is read-only
+func (c *Config) IsReadOnly() bool {
+	return c.readOnly.Load()
+}
+
+// CheckWritable reports whether the config file could be saved, without
+// changing it. When the file doesn't exist yet, its directory is checked.
+func (c *Config) CheckWritable() error {
+	file, err := os.OpenFile(c.filename, os.O_WRONLY, 0)
+	if err == nil {
+		return file.Close()
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+
+	probe, err := os.CreateTemp(filepath.Dir(c.filename), ".write-check-*")
+	if err != nil {
+		return err
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
 func (c *Config) Save() error {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	if c.IsReadOnly() {
+		return ErrReadOnly
+	}
 
+	c.saveMu.Lock()
+	defer c.saveMu.Unlock()
+
+	c.mu.RLock()
 	data, err := yaml.Marshal(c)
+	current := snapshotJobs(c.Jobs)
+	c.mu.RUnlock()
+
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -85,6 +241,61 @@ func (c *Config) Save() error {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
+	change := diffJobs(c.saved, current)
+	c.saved = current
+	if c.listener != nil && !change.Empty() {
+		c.listener(change)
+	}
+
+	return nil
+}
+
+// ErrJobExists is returned by CreateJob when the ID is already taken
+var ErrJobExists = errors.New("job already exists")
+
+// ErrJobNotFound is returned when no job has the given ID
+var ErrJobNotFound = errors.New("job not found")
+
+// ErrJobLimit is returned by CreateJob and AddJob when adding a job would
+// exceed the limit set with SetMaxJobs
+var ErrJobLimit = errors.New("job limit reached")
+
+// SetMaxJobs caps how many jobs the config may hold; 0 removes the cap.
+// Updates to existing jobs are never blocked by it.
+func (c *Config) SetMaxJobs(max int) {
+	c.maxJobs.Store(int64(max))
+}
+
+// MaxJobs returns the job limit, 0 when there is none
+func (c *Config) MaxJobs() int {
+	return int(c.maxJobs.Load())
+}
+
+// checkJobLimit reports whether one more job fits. The caller must hold
+// c.mu.
+func (c *Config) checkJobLimit() error {
+	if max := c.MaxJobs(); max > 0 && len(c.Jobs) >= max {
+		return fmt.Errorf("%w: at most %d jobs may be configured", ErrJobLimit, max)
+	}
+	return nil
+}
+
+// CreateJob adds a job only if no job has its ID, returning ErrJobExists
+// otherwise. The check and the insert happen under one lock.
+func (c *Config) CreateJob(job CronJob) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, existingJob := range c.Jobs {
+		if existingJob.ID == job.ID {
+			return fmt.Errorf("%w: %s", ErrJobExists, job.ID)
+		}
+	}
+
+	if err := c.checkJobLimit(); err != nil {
+		return err
+	}
+	c.Jobs = append(c.Jobs, job)
 	return nil
 }
 
@@ -99,6 +310,9 @@ func (c *Config) AddJob(job CronJob) error {
 		}
 	}
 
+	if err := c.checkJobLimit(); err != nil {
+		return err
+	}
 	c.Jobs = append(c.Jobs, job)
 	return nil
 }
@@ -114,7 +328,171 @@ func (c *Config) DeleteJob(id string) error {
 		}
 	}
 
-	return fmt.Errorf("job with id %s not found", id)
+	return fmt.Errorf("%w: %s", ErrJobNotFound, id)
+}
+
+// DeleteJobsMatching removes every job for which match returns true in a
+// single locked pass and returns the removed jobs
+func (c *Config) DeleteJobsMatching(match func(CronJob) bool) []CronJob {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	deleted := []CronJob{}
+	remaining := make([]CronJob, 0, len(c.Jobs))
+	for _, job := range c.Jobs {
+		if match(job) {
+			deleted = append(deleted, job)
+			continue
+		}
+		remaining = append(remaining, job)
+	}
+
+	c.Jobs = remaining
+	return deleted
+}
+
+// RestoreJobs puts back jobs that were deleted but could not be saved. A
+// job whose ID was taken again in the meantime is left out, and the job
+// limit does not apply since the jobs were configured before.
+func (c *Config) RestoreJobs(jobs []CronJob) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	existing := make(map[string]bool, len(c.Jobs))
+	for _, job := range c.Jobs {
+		existing[job.ID] = true
+	}
+	for _, job := range jobs {
+		if !existing[job.ID] {
+			c.Jobs = append(c.Jobs, job)
+		}
+	}
+}
+
+// RestoreJob undoes an edit to one job that could not be saved. previous,
+// as read before the edit, is put back in place; a nil previous means the
+// edit created the job, so it is removed again.
+func (c *Config) RestoreJob(id string, previous *CronJob) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, job := range c.Jobs {
+		if job.ID != id {
+			continue
+		}
+		if previous == nil {
+			c.Jobs = append(c.Jobs[:i], c.Jobs[i+1:]...)
+		} else {
+			c.Jobs[i] = *previous
+		}
+		return
+	}
+	if previous != nil {
+		c.Jobs = append(c.Jobs, *previous)
+	}
+}
+
+// JobPosition is a job's place in the config, as captured by JobOrder
+type JobPosition struct {
+	ID    string
+	Order int
+}
+
+// JobOrder returns the jobs' positions in config order, for RestoreOrder
+func (c *Config) JobOrder() []JobPosition {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	positions := make([]JobPosition, len(c.Jobs))
+	for i, job := range c.Jobs {
+		positions[i] = JobPosition{ID: job.ID, Order: job.Order}
+	}
+	return positions
+}
+
+// RestoreOrder puts back the job order captured by JobOrder, after a
+// reorder that could not be saved. Jobs added since go last.
+func (c *Config) RestoreOrder(positions []JobPosition) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	index := make(map[string]int, len(positions))
+	for i, position := range positions {
+		index[position.ID] = i
+	}
+	rank := func(job CronJob) int {
+		if i, ok := index[job.ID]; ok {
+			return i
+		}
+		return len(positions)
+	}
+
+	for i := range c.Jobs {
+		if at, ok := index[c.Jobs[i].ID]; ok {
+			c.Jobs[i].Order = positions[at].Order
+		}
+	}
+	sort.SliceStable(c.Jobs, func(i, j int) bool { return rank(c.Jobs[i]) < rank(c.Jobs[j]) })
+}
+
+// Job states reported by CronJob.State
+const (
+	StateDraft    = "draft"
+	StateTemplate = "template"
+	StateEnabled  = "enabled"
+	StateDisabled = "disabled"
+)
+
+// State returns the job's lifecycle state. Drafts are reported as such
+// even when Enabled is set.
+func (j CronJob) State() string {
+	switch {
+	case j.Draft:
+		return StateDraft
+	case j.Template:
+		return StateTemplate
+	case j.Enabled:
+		return StateEnabled
+	default:
+		return StateDisabled
+	}
+}
+
+// HasTag reports whether the job carries the given tag
+func (j CronJob) HasTag(tag string) bool {
+	for _, t := range j.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordReminderRun stores the outcome of a reminder's last run on it
+func (c *Config) RecordReminderRun(jobID, reminderID string, run ReminderRun) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range c.Jobs {
+		if c.Jobs[i].ID != jobID {
+			continue
+		}
+		// Copy the reminders so jobs handed out earlier don't change
+		reminders := append([]Reminder(nil), c.Jobs[i].Reminders...)
+		for j := range reminders {
+			if reminders[j].ID == reminderID {
+				reminders[j].LastStatus = run.Status
+				reminders[j].LastError = run.Error
+				reminders[j].LastRunAt = &run.At
+				reminders[j].FailedStep = run.FailedStep
+				c.Jobs[i].Reminders = reminders
+				return nil
+			}
+		}
+		return fmt.Errorf("reminder with id %s not found in job %s", reminderID, jobID)
+	}
+
+	return fmt.Errorf("%w: %s", ErrJobNotFound, jobID)
 }
 
 // DeleteReminder removes a reminder from a job by job ID and reminder ID
@@ -147,7 +525,34 @@ func (c *Config) DeleteReminder(jobID, reminderID string) error {
 		}
 	}
 
-	return fmt.Errorf("job with id %s not found", jobID)
+	return fmt.Errorf("%w: %s", ErrJobNotFound, jobID)
+}
+
+// RemovePastReminders drops every reminder of a job whose datetime is
+// before now and returns how many were removed
+func (c *Config) RemovePastReminders(jobID string, now time.Time) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, job := range c.Jobs {
+		if job.ID != jobID {
+			continue
+		}
+
+		kept := []Reminder{}
+		for _, reminder := range job.Reminders {
+			if reminder.Datetime.Before(now) {
+				continue
+			}
+			kept = append(kept, reminder)
+		}
+
+		removed := len(job.Reminders) - len(kept)
+		c.Jobs[i].Reminders = kept
+		return removed, nil
+	}
+
+	return 0, fmt.Errorf("%w: %s", ErrJobNotFound, jobID)
 }
 
 func (c *Config) GetJob(id string) (*CronJob, error) {
@@ -160,7 +565,7 @@ func (c *Config) GetJob(id string) (*CronJob, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("job with id %s not found", id)
+	return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
 }
 
 func (c *Config) GetAllJobs() []CronJob {
@@ -169,5 +574,57 @@ func (c *Config) GetAllJobs() []CronJob {
 
 	jobs := make([]CronJob, len(c.Jobs))
 	copy(jobs, c.Jobs)
+	sortJobs(jobs)
 	return jobs
+}
+
+// ReorderJobs assigns Order to jobs following the given list of IDs. Jobs
+// not listed keep their relative order after the listed ones.
+func (c *Config) ReorderJobs(ids []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	position := make(map[string]int, len(ids))
+	for i, id := range ids {
+		if _, dup := position[id]; dup {
+			return fmt.Errorf("job id %s listed more than once", id)
+		}
+		position[id] = i + 1
+	}
+
+	found := 0
+	for _, job := range c.Jobs {
+		if _, ok := position[job.ID]; ok {
+			found++
+		}
+	}
+	if found != len(ids) {
+		return fmt.Errorf("reorder list contains unknown job ids")
+	}
+
+	sortJobs(c.Jobs)
+	next := len(ids) + 1
+	for i := range c.Jobs {
+		if pos, ok := position[c.Jobs[i].ID]; ok {
+			c.Jobs[i].Order = pos
+		} else {
+			c.Jobs[i].Order = next
+			next++
+		}
+	}
+	sortJobs(c.Jobs)
+
+	return nil
+}
+
+// sortJobs orders jobs by Order, keeping config order for ties and placing
+// jobs without an Order last
+func sortJobs(jobs []CronJob) {
+	sort.SliceStable(jobs, func(i, j int) bool {
+		a, b := jobs[i].Order, jobs[j].Order
+		if a == 0 || b == 0 {
+			return a != 0 && b == 0
+		}
+		return a < b
+	})
 }
\ No newline at end of file
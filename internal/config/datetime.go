@@ -0,0 +1,121 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// datetimeLayouts are the reminder datetime formats accepted, tried in order.
+// Layouts without a zone are read in the default location.
+var datetimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04Z07:00",
+	"2006-01-02",
+}
+
+var (
+	locationMu      sync.RWMutex
+	defaultLocation = time.Local
+)
+
+// SetDefaultLocation sets the time zone cron schedules run in and that is
+// assumed for reminder datetimes written without one. It should be called
+// before loading the config and creating the scheduler.
+func SetDefaultLocation(loc *time.Location) {
+	locationMu.Lock()
+	defer locationMu.Unlock()
+
+	defaultLocation = loc
+}
+
+// DefaultLocation returns the time zone set with SetDefaultLocation
+func DefaultLocation() *time.Location {
+	locationMu.RLock()
+	defer locationMu.RUnlock()
+
+	return defaultLocation
+}
+
+// ParseDatetime parses a reminder datetime in any accepted format, such as
+// "2024-01-02T15:04:05Z", "2024-01-02 15:04" or "2024-01-02"
+func ParseDatetime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	loc := DefaultLocation()
+
+	for _, layout := range datetimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid datetime %q: use a format like 2024-01-02T15:04:05Z, 2024-01-02 15:04 or 2024-01-02", value)
+}
+
+// reminderFields has Reminder's fields without its custom unmarshalers
+type reminderFields Reminder
+
+// UnmarshalJSON accepts any datetime format understood by ParseDatetime
+func (r *Reminder) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		reminderFields
+		Datetime string `json:"datetime"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*r = Reminder(aux.reminderFields)
+	if aux.Datetime == "" {
+		return nil
+	}
+
+	t, err := ParseDatetime(aux.Datetime)
+	if err != nil {
+		return err
+	}
+	r.Datetime = t
+	return nil
+}
+
+// UnmarshalYAML accepts any datetime format understood by ParseDatetime
+func (r *Reminder) UnmarshalYAML(node *yaml.Node) error {
+	var datetime time.Time
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value != "datetime" {
+				continue
+			}
+
+			t, err := ParseDatetime(node.Content[i+1].Value)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", node.Content[i+1].Line, err)
+			}
+			datetime = t
+
+			// Decode the rest without the datetime, which is set below
+			content := append([]*yaml.Node{}, node.Content[:i]...)
+			node = &yaml.Node{Kind: node.Kind, Tag: node.Tag, Content: append(content, node.Content[i+2:]...)}
+			break
+		}
+	}
+
+	var fields reminderFields
+	if err := node.Decode(&fields); err != nil {
+		return err
+	}
+
+	*r = Reminder(fields)
+	r.Datetime = datetime
+	return nil
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// LatenessLimit returns how late a scheduled tick of the job may start
+// before it is skipped. An unset MaxLateness is 0, meaning no limit.
+func (j CronJob) LatenessLimit() (time.Duration, error) {
+	if j.MaxLateness == "" {
+		return 0, nil
+	}
+	limit, err := time.ParseDuration(j.MaxLateness)
+	if err != nil {
+		return 0, fmt.Errorf("invalid lateness %q: %w", j.MaxLateness, err)
+	}
+	if limit <= 0 {
+		return 0, fmt.Errorf("lateness %q must be positive", j.MaxLateness)
+	}
+	return limit, nil
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietHours is a daily window during which a job's scheduled ticks are
+// skipped. The window may wrap past midnight, such as 22:00 to 07:00.
+type QuietHours struct {
+	Start          string   `yaml:"start" json:"start"`                                         // Time of day the window opens, "HH:MM"
+	End            string   `yaml:"end" json:"end"`                                             // Time of day the window closes, "HH:MM"
+	Timezone       string   `yaml:"timezone,omitempty" json:"timezone,omitempty"`               // IANA zone, defaults to the zone used for reminder datetimes
+	Days           []string `yaml:"days,omitempty" json:"days,omitempty"`                       // Weekdays the window opens on, such as "mon"; empty means every day
+	DeferReminders bool     `yaml:"defer_reminders,omitempty" json:"defer_reminders,omitempty"` // Fire reminders due in the window when it closes instead of on time
+}
+
+// quietWindow is a parsed QuietHours
+type quietWindow struct {
+	start, end int // Minutes after midnight
+	loc        *time.Location
+	days       map[time.Weekday]bool // nil means every day
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Validate returns an error when the times of day, timezone or days can't
+// be parsed. Unset quiet hours are valid.
+func (q *QuietHours) Validate() error {
+	if q == nil {
+		return nil
+	}
+	_, err := q.parse()
+	return err
+}
+
+func (q QuietHours) parse() (quietWindow, error) {
+	var w quietWindow
+	var err error
+
+	if w.start, err = parseTimeOfDay(q.Start); err != nil {
+		return w, fmt.Errorf("invalid quiet hours start: %w", err)
+	}
+	if w.end, err = parseTimeOfDay(q.End); err != nil {
+		return w, fmt.Errorf("invalid quiet hours end: %w", err)
+	}
+	if w.start == w.end {
+		return w, fmt.Errorf("quiet hours start and end are both %s", q.Start)
+	}
+
+	if q.Timezone == "" {
+		w.loc = DefaultLocation()
+	} else if w.loc, err = time.LoadLocation(q.Timezone); err != nil {
+		return w, fmt.Errorf("invalid quiet hours timezone: %w", err)
+	}
+
+	for _, day := range q.Days {
+		name := strings.ToLower(strings.TrimSpace(day))
+		if len(name) < 3 {
+			return w, fmt.Errorf("invalid quiet hours day %q", day)
+		}
+		weekday, ok := weekdays[name[:3]]
+		if !ok || !strings.HasPrefix(strings.ToLower(weekday.String()), name) {
+			return w, fmt.Errorf("invalid quiet hours day %q", day)
+		}
+		if w.days == nil {
+			w.days = make(map[time.Weekday]bool)
+		}
+		w.days[weekday] = true
+	}
+
+	return w, nil
+}
+
+// parseTimeOfDay parses "HH:MM" into minutes after midnight
+func parseTimeOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a time of day like 22:00", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Until reports whether t falls inside the quiet window and, if so, when
+// the window closes. Unset or invalid quiet hours are never quiet.
+func (q *QuietHours) Until(t time.Time) (time.Time, bool) {
+	if q == nil {
+		return time.Time{}, false
+	}
+	w, err := q.parse()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	local := t.In(w.loc)
+
+	// A window that wraps past midnight may have opened the day before
+	for _, offset := range []int{-1, 0} {
+		day := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, w.loc)
+		if w.days != nil && !w.days[day.Weekday()] {
+			continue
+		}
+
+		closingDay := day.Day()
+		if w.end < w.start {
+			closingDay++
+		}
+		opens := time.Date(day.Year(), day.Month(), day.Day(), 0, w.start, 0, 0, w.loc)
+		closes := time.Date(day.Year(), day.Month(), closingDay, 0, w.end, 0, 0, w.loc)
+		if !local.Before(opens) && local.Before(closes) {
+			return closes, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,25 @@
+package config
+
+import "fmt"
+
+// AssignReminderIDs gives every reminder without an ID a generated UUID
+func (j *CronJob) AssignReminderIDs() {
+	for i := range j.Reminders {
+		if j.Reminders[i].ID == "" {
+			j.Reminders[i].ID = NewUUID()
+		}
+	}
+}
+
+// ValidateReminderIDs returns an error when two of the job's reminders
+// share an ID, which would make updating or removing them ambiguous
+func (j CronJob) ValidateReminderIDs() error {
+	seen := make(map[string]bool, len(j.Reminders))
+	for _, reminder := range j.Reminders {
+		if seen[reminder.ID] {
+			return fmt.Errorf("duplicate reminder id %q in job %s", reminder.ID, j.ID)
+		}
+		seen[reminder.ID] = true
+	}
+	return nil
+}
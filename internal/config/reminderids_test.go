@@ -0,0 +1,53 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestAssignReminderIDs(t *testing.T) {
+	job := CronJob{ID: "job", Reminders: []Reminder{{ID: "kept"}, {}, {}}}
+	job.AssignReminderIDs()
+
+	if job.Reminders[0].ID != "kept" {
+		t.Errorf("explicit id replaced with %q", job.Reminders[0].ID)
+	}
+	for _, reminder := range job.Reminders[1:] {
+		if !uuidPattern.MatchString(reminder.ID) {
+			t.Errorf("generated id %q is not a UUID", reminder.ID)
+		}
+	}
+	if job.Reminders[1].ID == job.Reminders[2].ID {
+		t.Errorf("generated ids repeat: %q", job.Reminders[1].ID)
+	}
+	if err := job.ValidateReminderIDs(); err != nil {
+		t.Errorf("ValidateReminderIDs after assigning: %v", err)
+	}
+}
+
+func TestValidateReminderIDs(t *testing.T) {
+	for name, tc := range map[string]struct {
+		ids     []string
+		wantErr bool
+	}{
+		"none":      {nil, false},
+		"unique":    {[]string{"a", "b"}, false},
+		"duplicate": {[]string{"a", "b", "a"}, true},
+	} {
+		job := CronJob{ID: "job"}
+		for _, id := range tc.ids {
+			job.Reminders = append(job.Reminders, Reminder{ID: id})
+		}
+
+		err := job.ValidateReminderIDs()
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: err = %v, want error %v", name, err, tc.wantErr)
+		}
+		if err != nil && !strings.Contains(err.Error(), `"a"`) {
+			t.Errorf("%s: error %q doesn't name the duplicate id", name, err)
+		}
+	}
+}
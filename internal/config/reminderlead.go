@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrReminderTooFar is returned by CheckReminderLeadTime for a reminder set
+// further ahead than the limit set with SetMaxReminderLeadTime
+var ErrReminderTooFar = errors.New("reminder is too far in the future")
+
+// SetMaxReminderLeadTime caps how far ahead a reminder may be saved; 0
+// removes the cap. Reminders already in the config file are not affected.
+func (c *Config) SetMaxReminderLeadTime(max time.Duration) {
+	c.maxLeadTime.Store(int64(max))
+}
+
+// MaxReminderLeadTime returns the reminder lead time limit, 0 when there is
+// none
+func (c *Config) MaxReminderLeadTime() time.Duration {
+	return time.Duration(c.maxLeadTime.Load())
+}
+
+// CheckReminderLeadTime returns an error naming the first enabled reminder
+// of job that is due further ahead than the limit, usually a mistyped year.
+// Reminders the stored job already has at the same time are let through so
+// they don't block other edits to the job.
+func (c *Config) CheckReminderLeadTime(job CronJob) error {
+	max := c.MaxReminderLeadTime()
+	if max <= 0 {
+		return nil
+	}
+
+	stored := map[string]time.Time{}
+	if existing, err := c.GetJob(job.ID); err == nil {
+		for _, reminder := range existing.Reminders {
+			stored[reminder.ID] = reminder.Datetime
+		}
+	}
+
+	latest := time.Now().Add(max)
+	for _, reminder := range job.Reminders {
+		if !reminder.IsEnabled() || !reminder.Datetime.After(latest) {
+			continue
+		}
+		if at, ok := stored[reminder.ID]; ok && at.Equal(reminder.Datetime) {
+			continue
+		}
+		return fmt.Errorf("%w: reminder %s is due %s, more than %s ahead", ErrReminderTooFar, reminder.ID, reminder.Datetime.Format(time.RFC3339), max)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeepReminderRunState(t *testing.T) {
+	ranAt := time.Now()
+	stored := []Reminder{{ID: "r1", LastStatus: "failure", LastError: "boom", LastRunAt: &ranAt, FailedStep: ReminderStepSecondary}}
+
+	forged := time.Now().Add(-time.Hour)
+	job := CronJob{Reminders: []Reminder{
+		{ID: "r1", Text: "edited"},
+		{ID: "r2", LastStatus: "failure", LastError: "forged", LastRunAt: &forged, FailedStep: ReminderStepPrimary},
+	}}
+	job.KeepReminderRunState(stored)
+
+	kept := job.Reminders[0]
+	if kept.Text != "edited" || kept.LastStatus != "failure" || kept.LastError != "boom" || kept.LastRunAt != &ranAt || kept.FailedStep != ReminderStepSecondary {
+		t.Errorf("r1 = %+v, want the edit with the stored run state", kept)
+	}
+	if cleared := job.Reminders[1]; cleared.LastStatus != "" || cleared.LastError != "" || cleared.LastRunAt != nil || cleared.FailedStep != "" {
+		t.Errorf("r2 = %+v, want the client's run state cleared", cleared)
+	}
+}
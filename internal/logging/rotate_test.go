@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestFile(t *testing.T, maxBackups int) (*RotatingFile, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cron.log")
+	r, err := NewRotatingFile(path, 0, maxBackups)
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	t.Cleanup(func() { r.Close() })
+	r.maxSize = 10
+	return r, path
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	return string(data)
+}
+
+func TestRotatingFileRotates(t *testing.T) {
+	r, path := newTestFile(t, 2)
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	for file, want := range map[string]string{path: "third\n", path + ".1": "second\n", path + ".2": "first\n"} {
+		if got := readFile(t, file); got != want {
+			t.Errorf("%s = %q, want %q", filepath.Base(file), got, want)
+		}
+	}
+}
+
+func TestRotatingFileKeepsWritingWhenRotateFails(t *testing.T) {
+	r, path := newTestFile(t, 1)
+
+	// A non-empty directory in the way of the backup makes the rename fail
+	if err := os.MkdirAll(filepath.Join(path+".1", "blocked"), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	for _, line := range []string{"first\n", "second\n", "3\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatalf("Write(%q): %v", line, err)
+		}
+	}
+
+	got := readFile(t, path)
+	for _, want := range []string{"first\n", "second\n", "3\n", "[LOG_ROTATE_ERROR]"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("log file is missing %q:\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "[LOG_ROTATE_ERROR]"); n != 1 {
+		t.Errorf("rotation failure noted %d times, want once per maxSize bytes:\n%s", n, got)
+	}
+}
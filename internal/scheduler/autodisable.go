@@ -0,0 +1,113 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"cron-microservice/internal/config"
+)
+
+// trackFailures counts consecutive failed runs of a job and disables the
+// job once its MaxConsecutiveFailures is exceeded. Successful runs reset
+// the count; skipped runs leave it unchanged.
+func (s *Scheduler) trackFailures(job config.CronJob, r *run) {
+	r.mu.Lock()
+	status := r.record.Status
+	lastErr := r.record.Error
+	r.mu.Unlock()
+
+	s.mu.Lock()
+	switch status {
+	case StatusSuccess:
+		delete(s.failures, job.ID)
+		s.mu.Unlock()
+		return
+	case StatusSkipped:
+		s.mu.Unlock()
+		return
+	}
+	s.failures[job.ID]++
+	failures := s.failures[job.ID]
+	s.mu.Unlock()
+
+	if job.MaxConsecutiveFailures <= 0 || failures <= job.MaxConsecutiveFailures {
+		return
+	}
+
+	reason := fmt.Sprintf("auto-disabled after %d consecutive failures, last error: %s", failures, lastErr)
+	r.mu.Lock()
+	r.record.Reason = reason
+	r.mu.Unlock()
+
+	s.autoDisable(job.ID, reason, failures, lastErr)
+}
+
+// autoDisable turns a job off, persists the change with the reason, removes
+// its cron entry and sends the job's OnAutoDisable alert
+func (s *Scheduler) autoDisable(jobID, reason string, failures int, lastErr string) {
+	// A read-only config can't record the job as disabled, so it is left
+	// running rather than disabled only until the next restart
+	if s.config.IsReadOnly() {
+		s.logger.Printf("[JOB_AUTO_DISABLE_SKIPPED] Job %s would be %s, but the configuration is read-only; it stays enabled", jobID, reason)
+		return
+	}
+
+	current, err := s.config.GetJob(jobID)
+	if err != nil {
+		s.logger.Printf("[JOB_AUTO_DISABLE_ERROR] Failed to load job %s: %v", jobID, err)
+		return
+	}
+
+	job := *current
+	job.Enabled = false
+	job.DisabledReason = reason
+
+	if err := s.config.AddJob(job); err != nil {
+		s.logger.Printf("[JOB_AUTO_DISABLE_ERROR] Failed to update job %s: %v", jobID, err)
+		return
+	}
+	if err := s.config.Save(); err != nil {
+		s.logger.Printf("[JOB_AUTO_DISABLE_ERROR] Failed to save config for job %s: %v", jobID, err)
+	}
+	if err := s.AddJob(job); err != nil {
+		s.logger.Printf("[JOB_AUTO_DISABLE_ERROR] Failed to unschedule job %s: %v", jobID, err)
+	}
+
+	s.logger.Printf("[JOB_AUTO_DISABLED] Job %s %s", jobID, reason)
+
+	if job.OnAutoDisable == nil || !job.OnAutoDisable.Enabled {
+		return
+	}
+
+	ctx := withStep(context.Background(), job.ID, "on_auto_disable")
+	alert := *job.OnAutoDisable
+	variables := map[string]interface{}{
+		"job":      job.ID,
+		"name":     job.Name,
+		"reason":   reason,
+		"failures": failures,
+		"error":    lastErr,
+	}
+
+	switch {
+	case alert.BodyTemplate != "":
+		body, err := s.renderTemplate(ctx, alert, alert.BodyTemplate, variables)
+		if err != nil {
+			s.logger.Printf("[JOB_AUTO_DISABLE_ERROR] Failed to process alert template for job %s: %v", jobID, err)
+		} else {
+			alert.Body = body
+		}
+	case alert.Body == "":
+		body, _ := json.Marshal(map[string]interface{}{
+			"job":      job.ID,
+			"reason":   reason,
+			"failures": failures,
+		})
+		alert.Body = string(body)
+	}
+
+	if _, err := s.executeWebhook(ctx, s.renderRequest(ctx, alert, variables)); err != nil {
+		s.logger.Printf("[JOB_AUTO_DISABLE_ERROR] Failed to send auto-disable alert for job %s: %v", jobID, err)
+	}
+}
@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"sort"
+	"time"
+)
+
+// armFollowers starts a one-shot timer for every enabled job chained to
+// run after jobID, each firing at the follower's offset from now. Followers
+// go through the same checks as a scheduled tick when their timer fires,
+// and arm their own followers in turn.
+func (s *Scheduler) armFollowers(jobID string) {
+	s.mu.RLock()
+	var followers []string
+	for id, job := range s.loaded {
+		if job.AfterJob == jobID && job.Enabled && !job.Draft && !job.Template {
+			followers = append(followers, id)
+		}
+	}
+	s.mu.RUnlock()
+	sort.Strings(followers)
+
+	for _, followerID := range followers {
+		s.mu.RLock()
+		follower := s.loaded[followerID]
+		s.mu.RUnlock()
+
+		offset, err := follower.AfterOffset()
+		if err != nil {
+			s.logger.Printf("[JOB_CHAIN_ERROR] Not running job %s after job %s: %v", followerID, jobID, err)
+			continue
+		}
+
+		s.logger.Printf("[JOB_CHAIN_ARMED] Job %s fired, running job %s in %v", jobID, followerID, offset)
+		time.AfterFunc(offset, func() { s.runFollower(jobID, followerID) })
+	}
+}
+
+// runFollower runs a chained job when its timer fires, unless it was
+// deleted, disabled or unchained in the meantime
+func (s *Scheduler) runFollower(jobID, followerID string) {
+	s.mu.RLock()
+	follower, ok := s.loaded[followerID]
+	s.mu.RUnlock()
+
+	if !ok || follower.AfterJob != jobID || !follower.Enabled || follower.Draft || follower.Template {
+		s.logger.Printf("[JOB_CHAIN_SKIP] Job %s no longer runs after job %s, skipping", followerID, jobID)
+		return
+	}
+	s.scheduledAction(follower, nil)()
+}
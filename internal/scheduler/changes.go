@@ -0,0 +1,177 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// responseHashes remembers a hash of each change-detecting job's last
+// primary response, persisted to a JSON file so restarts don't re-notify.
+// The previous body itself is only kept in memory, for diffs.
+type responseHashes struct {
+	mu     sync.Mutex
+	path   string
+	hashes map[string]string
+	bodies map[string]string
+}
+
+func newResponseHashes() *responseHashes {
+	return &responseHashes{hashes: make(map[string]string), bodies: make(map[string]string)}
+}
+
+// SetResponseHashFile sets where response hashes for notify_on_change jobs
+// are persisted and loads any hashes already stored there
+func (s *Scheduler) SetResponseHashFile(path string) error {
+	s.responseHashes.mu.Lock()
+	defer s.responseHashes.mu.Unlock()
+
+	s.responseHashes.path = path
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read response hash file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &s.responseHashes.hashes); err != nil {
+		return fmt.Errorf("failed to parse response hash file: %w", err)
+	}
+	return nil
+}
+
+// responseChanged reports whether a job's latest response differs from the
+// previous one. The first response seen only sets the baseline. A changed
+// response is not recorded here: the caller commits it with
+// commitResponse once the notification went out, so a failed one is sent
+// again on the next run. When the previous body is known, a line diff is
+// put in scope as {{diff}}.
+func (s *Scheduler) responseChanged(ctx context.Context, jobID, response string, scope map[string]interface{}) bool {
+	logger := s.logFor(ctx)
+	hash := responseHash(response)
+
+	h := s.responseHashes
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	previous, known := h.hashes[jobID]
+	if known && previous == hash {
+		h.bodies[jobID] = response
+		logger.Printf("[JOB_UNCHANGED] Response for job %s is unchanged, not notifying", jobID)
+		return false
+	}
+
+	if !known {
+		if err := h.record(jobID, hash, response); err != nil {
+			logger.Printf("[JOB_CHANGE_SAVE_ERROR] Failed to persist response hash for job %s: %v", jobID, err)
+		}
+		logger.Printf("[JOB_CHANGE_BASELINE] Recorded first response for job %s", jobID)
+		return false
+	}
+
+	if previousBody, haveBody := h.bodies[jobID]; haveBody {
+		scope["diff"] = lineDiff(previousBody, response)
+	}
+	logger.Printf("[JOB_CHANGED] Response for job %s changed", jobID)
+	return true
+}
+
+// commitResponse records a changed response as the one later runs compare
+// against, unless the run failed and its notification needs to be retried
+func (s *Scheduler) commitResponse(ctx context.Context, jobID, response string) {
+	run := runFrom(ctx)
+	run.mu.Lock()
+	status := run.record.Status
+	run.mu.Unlock()
+	if status == StatusFailure {
+		s.logFor(ctx).Printf("[JOB_CHANGE_PENDING] Run of job %s failed, the change will be notified again on the next run", jobID)
+		return
+	}
+
+	h := s.responseHashes
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.record(jobID, responseHash(response), response); err != nil {
+		s.logFor(ctx).Printf("[JOB_CHANGE_SAVE_ERROR] Failed to persist response hash for job %s: %v", jobID, err)
+	}
+}
+
+// record stores a job's response hash and body and persists the hashes.
+// The caller must hold h.mu.
+func (h *responseHashes) record(jobID, hash, response string) error {
+	h.hashes[jobID] = hash
+	h.bodies[jobID] = response
+	return h.save()
+}
+
+// responseHash returns the hex SHA-256 of a response
+func responseHash(response string) string {
+	sum := sha256.Sum256([]byte(response))
+	return hex.EncodeToString(sum[:])
+}
+
+// forget drops a deleted job's state
+func (h *responseHashes) forget(jobID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, known := h.hashes[jobID]; !known {
+		return
+	}
+	delete(h.hashes, jobID)
+	delete(h.bodies, jobID)
+	h.save()
+}
+
+// save writes the hashes to the file. The caller must hold h.mu.
+func (h *responseHashes) save() error {
+	if h.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(h.hashes, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(h.path, data, 0644)
+}
+
+// lineDiff is a simple diff listing lines only in before as "- line" and
+// lines only in after as "+ line", in their original order
+func lineDiff(before, after string) string {
+	beforeLines := strings.Split(before, "\n")
+	afterLines := strings.Split(after, "\n")
+
+	inBefore := make(map[string]int, len(beforeLines))
+	for _, line := range beforeLines {
+		inBefore[line]++
+	}
+	inAfter := make(map[string]int, len(afterLines))
+	for _, line := range afterLines {
+		inAfter[line]++
+	}
+
+	var diff []string
+	for _, line := range beforeLines {
+		if inAfter[line] > 0 {
+			inAfter[line]--
+			continue
+		}
+		diff = append(diff, "- "+line)
+	}
+	for _, line := range afterLines {
+		if inBefore[line] > 0 {
+			inBefore[line]--
+			continue
+		}
+		diff = append(diff, "+ "+line)
+	}
+	return strings.Join(diff, "\n")
+}
@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/ianaindex"
+)
+
+// decodeResponse transcodes a response body from charset to UTF-8. Without a
+// charset the body is assumed to be UTF-8 already and returned unchanged.
+// Charsets are looked up by IANA name or alias, such as "ISO-8859-1" or
+// "latin1", falling back to the labels browsers accept.
+func decodeResponse(charset string, body []byte) ([]byte, error) {
+	if charset == "" {
+		return body, nil
+	}
+
+	enc, err := ianaindex.IANA.Encoding(charset)
+	if err != nil || enc == nil {
+		if enc, err = htmlindex.Get(charset); err != nil {
+			return nil, fmt.Errorf("unknown response charset %q", charset)
+		}
+	}
+	if enc == encoding.Nop {
+		return body, nil
+	}
+
+	decoded, err := enc.NewDecoder().Bytes(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response as %s: %w", charset, err)
+	}
+	return decoded, nil
+}
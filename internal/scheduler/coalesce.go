@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"context"
+
+	"cron-microservice/internal/config"
+)
+
+// Coalescing policies for CronJob.Coalesce, applied when a scheduled tick
+// arrives while an earlier run of the same job is still in progress
+const (
+	CoalesceQueue   = "queue"   // Run anyway, alongside the earlier run (default)
+	CoalesceSkip    = "skip"    // Drop the new tick
+	CoalesceReplace = "replace" // Cancel the earlier run and start the new one
+)
+
+// activeRun is a run registered as in progress, with the context that
+// carries it and cancels it
+type activeRun struct {
+	run    *run
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// beginRun starts a run of a job and registers it as in progress until
+// endRun is called
+func (s *Scheduler) beginRun(job config.CronJob, trigger string) *activeRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.startActive(job, trigger)
+}
+
+// startActive starts a run and registers it in s.active. The caller must
+// hold s.mu.
+func (s *Scheduler) startActive(job config.CronJob, trigger string) *activeRun {
+	r := s.startExecution(job.ID, trigger)
+	ctx, cancel := s.runContext(job, r)
+	if s.active[job.ID] == nil {
+		s.active[job.ID] = make(map[*run]context.CancelFunc)
+	}
+	s.active[job.ID][r] = cancel
+	return &activeRun{run: r, ctx: ctx, cancel: cancel}
+}
+
+// endRun removes a run registered by beginRun or coalesce from s.active
+func (s *Scheduler) endRun(jobID string, r *run) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.active[jobID], r)
+	if len(s.active[jobID]) == 0 {
+		delete(s.active, jobID)
+	}
+}
+
+// coalesce applies a job's coalescing policy to a new scheduled tick. When
+// the tick should run, its run is started and registered under the same
+// lock as the decision, so two ticks can't both see the job as idle, and
+// it is returned for executeRun. A nil run means the tick is dropped.
+func (s *Scheduler) coalesce(job config.CronJob) *activeRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	running := s.active[job.ID]
+	if len(running) == 0 {
+		return s.startActive(job, TriggerSchedule)
+	}
+
+	switch job.Coalesce {
+	case CoalesceSkip:
+		s.logger.Printf("[JOB_COALESCED_SKIP] Skipping tick for job %s, %d run(s) still in progress", job.ID, len(running))
+		return nil
+	case CoalesceReplace:
+		// Only scheduled runs are replaced; manual and invoked runs were
+		// asked for and finish on their own
+		replaced := 0
+		for r, cancel := range running {
+			if r.replace() {
+				cancel()
+				replaced++
+			}
+		}
+		s.logger.Printf("[JOB_COALESCED_REPLACE] Cancelled %d of %d run(s) of job %s in favour of the new tick", replaced, len(running), job.ID)
+		return s.startActive(job, TriggerSchedule)
+	default:
+		return s.startActive(job, TriggerSchedule)
+	}
+}
+
+// replace marks a scheduled run as replaced by a newer tick and reports
+// whether it was one. A replaced run is recorded as skipped rather than
+// failed, so its cancellation neither counts toward the job's consecutive
+// failures nor fires the failure webhook.
+func (r *run) replace() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.record.Trigger != TriggerSchedule {
+		return false
+	}
+	r.replaced = true
+	r.record.Status = StatusSkipped
+	r.record.Reason = "replaced"
+	r.record.Error = ""
+	r.record.ErrorKind = ""
+	return true
+}
+
+// wasReplaced reports whether the run was cancelled in favour of a newer
+// tick
+func (r *run) wasReplaced() bool {
+	if r == nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.replaced
+}
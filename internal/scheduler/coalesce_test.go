@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+func TestCoalesceSkipRunsOneOfSimultaneousTicks(t *testing.T) {
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	job := config.CronJob{
+		ID:       "job",
+		Name:     "job",
+		Schedule: "@every 1h",
+		Enabled:  true,
+		Coalesce: CoalesceSkip,
+		Primary:  config.WebhookConfig{URL: server.URL, Method: http.MethodGet, Enabled: true},
+	}
+	s, logs := newTestScheduler(t, job)
+
+	// The ticks race to decide; only the first may see the job as idle
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			s.scheduledAction(job, nil)()
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("%d simultaneous ticks ran, want 1\n%s", got, logs.String())
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.active) != 0 {
+		t.Errorf("%d jobs still registered as running", len(s.active))
+	}
+}
+
+func TestCoalesceRegistersTheRunItAllows(t *testing.T) {
+	job := config.CronJob{ID: "job", Name: "job", Coalesce: CoalesceSkip}
+	s, _ := newTestScheduler(t, job)
+
+	first := s.coalesce(job)
+	if first == nil {
+		t.Fatal("the first tick of an idle job was dropped")
+	}
+	defer first.cancel()
+
+	// The first run counts as in progress before executeRun is reached
+	if second := s.coalesce(job); second != nil {
+		second.cancel()
+		t.Error("a second tick ran alongside one that coalesce had already allowed")
+	}
+
+	s.endRun(job.ID, first.run)
+	if third := s.coalesce(job); third == nil {
+		t.Error("a tick was dropped after the earlier run ended")
+	} else {
+		third.cancel()
+	}
+}
@@ -0,0 +1,144 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/itchyny/gojq"
+
+	"cron-microservice/internal/config"
+)
+
+// computeVariables evaluates a webhook's ComputedVars and stores the results
+// in scope. Each program runs against every other variable in scope as one
+// jq object, so {"a": .x + .y} style arithmetic, string functions and
+// base64 (@base64) can combine extracted values; names with dots are read
+// as .["now.date"]. Computed variables don't see each other, which keeps
+// evaluating them again before the next render idempotent. A step renders
+// its body and then its request from the same scope, so the run reuses the
+// values computed from identical input instead of running jq twice.
+func (s *Scheduler) computeVariables(ctx context.Context, webhook config.WebhookConfig, scope map[string]interface{}) {
+	if len(webhook.ComputedVars) == 0 {
+		return
+	}
+	logger := s.logFor(ctx)
+
+	// Round-trip through JSON so every value has a type jq understands
+	input := make(map[string]interface{}, len(scope))
+	for name, value := range scope {
+		if _, computed := webhook.ComputedVars[name]; !computed {
+			input[name] = value
+		}
+	}
+	encoded, err := json.Marshal(input)
+	if err != nil {
+		logger.Printf("[JQ_COMPUTED_ERROR] Failed to encode variables: %v", err)
+		return
+	}
+	var data interface{}
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		logger.Printf("[JQ_COMPUTED_ERROR] Failed to decode variables: %v", err)
+		return
+	}
+
+	names := make([]string, 0, len(webhook.ComputedVars))
+	for name := range webhook.ComputedVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	key := computedKey(encoded, names, webhook.ComputedVars)
+	r := runFrom(ctx)
+	if values, ok := r.cachedComputed(key); ok {
+		for name, v := range values {
+			scope[name] = v
+		}
+		return
+	}
+	values := make(map[string]interface{}, len(names))
+	defer func() { r.cacheComputed(key, values) }()
+
+	for _, name := range names {
+		program := webhook.ComputedVars[name]
+		query, err := gojq.Parse(program)
+		if err != nil {
+			logger.Printf("[JQ_COMPUTED_ERROR] Failed to parse computed variable '%s' (%s): %v", name, program, err)
+			continue
+		}
+
+		jqCtx, cancel := context.WithTimeout(ctx, jqTimeout)
+		v, ok := query.RunWithContext(jqCtx, data).Next()
+		cancel()
+		if !ok {
+			logger.Printf("[JQ_COMPUTED_EMPTY] Computed variable '%s' produced no value", name)
+			continue
+		}
+		if err, isErr := v.(error); isErr {
+			logger.Printf("[JQ_COMPUTED_ERROR] Failed to compute variable '%s': %v", name, err)
+			continue
+		}
+		if v, ok = s.limitVariable(ctx, name, v); !ok {
+			continue
+		}
+
+		runFrom(ctx).redact(map[string]interface{}{name: v})
+		if previous, known := scope[name]; !known || !reflect.DeepEqual(previous, v) {
+			logger.Printf("[JQ_COMPUTED] Computed variable '%s' = %v", name, v)
+		}
+		scope[name] = v
+		values[name] = v
+	}
+}
+
+// computedValues are the variables a run last computed, with a digest of
+// the programs and input they came from
+type computedValues struct {
+	key    [sha256.Size]byte
+	values map[string]interface{}
+}
+
+// computedKey digests computed variable programs and the encoded variables
+// they run against
+func computedKey(encoded []byte, names []string, programs map[string]string) [sha256.Size]byte {
+	h := sha256.New()
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write([]byte(programs[name]))
+		h.Write([]byte{0})
+	}
+	h.Write(encoded)
+
+	var key [sha256.Size]byte
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+// cachedComputed returns the values the run last computed when they came
+// from the same programs and input
+func (r *run) cachedComputed(key [sha256.Size]byte) (map[string]interface{}, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.computed.values == nil || r.computed.key != key {
+		return nil, false
+	}
+	return r.computed.values, true
+}
+
+// cacheComputed keeps the values the run computed for reuse
+func (r *run) cacheComputed(key [sha256.Size]byte, values map[string]interface{}) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.computed = computedValues{key: key, values: values}
+}
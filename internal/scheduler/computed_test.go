@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cron-microservice/internal/config"
+)
+
+func TestComputedVariablesOncePerStep(t *testing.T) {
+	var body, header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body, header = string(b), r.Header.Get("X-Stamp")
+	}))
+	defer server.Close()
+
+	// jq's now differs on every evaluation, so a second computation would
+	// give the header another value than the body
+	job := config.CronJob{
+		ID:      "job",
+		Name:    "job",
+		Enabled: true,
+		Primary: config.WebhookConfig{
+			URL:          server.URL,
+			Method:       http.MethodPost,
+			BodyTemplate: `{{stamp}}`,
+			Headers:      map[string]string{"X-Stamp": "{{stamp}}"},
+			ComputedVars: map[string]string{"stamp": "now"},
+			Enabled:      true,
+		},
+	}
+	s, _ := newTestScheduler(t, job)
+
+	if r := s.executeJob(job, TriggerManual, nil); r.record.Status != StatusSuccess {
+		t.Fatalf("run status = %s (%s), want success", r.record.Status, r.record.Error)
+	}
+	if body == "" || body == "{{stamp}}" || body != header {
+		t.Errorf("body %q and header %q, want the same computed value", body, header)
+	}
+}
@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/itchyny/gojq"
+)
+
+// evaluateCondition runs a jq expression against a JSON document and
+// reports whether its first result is truthy (anything but null or false).
+// An expression that produces no results is false, and one that runs past
+// jqTimeout fails.
+func evaluateCondition(jsonData, expression string) (bool, error) {
+	query, err := gojq.Parse(expression)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse jq expression '%s': %w", expression, err)
+	}
+
+	var data interface{}
+	if err := json.Unmarshal([]byte(jsonData), &data); err != nil {
+		return false, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), jqTimeout)
+	defer cancel()
+
+	v, ok := query.RunWithContext(ctx, data).Next()
+	if !ok {
+		return false, nil
+	}
+	if err, ok := v.(error); ok {
+		return false, fmt.Errorf("failed to evaluate jq expression '%s': %w", expression, err)
+	}
+
+	return v != nil && v != false, nil
+}
@@ -0,0 +1,148 @@
+package scheduler
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"cron-microservice/internal/config"
+)
+
+// blockPattern matches the {{if ...}}, {{else}} and {{end}} tags of
+// conditional template blocks
+var blockPattern = regexp.MustCompile(`\{\{\s*(if\s[^{}]*?|else|end)\s*\}\}`)
+
+// renderConditionals resolves conditional blocks before placeholders are
+// substituted, keeping the text of the branches that apply:
+//
+//	{{if token}}"token": "{{token}}",{{end}}
+//	{{if hasVar "token"}}...{{else}}...{{end}}
+//
+// {{if name}} (or {{if .name}}) holds when the variable is set and not
+// empty; {{if hasVar "name"}} holds when it is set at all, even to an empty
+// value. Blocks may be nested.
+func renderConditionals(templateStr string, variables map[string]interface{}) (string, error) {
+	matches := blockPattern.FindAllStringSubmatchIndex(templateStr, -1)
+	if len(matches) == 0 {
+		return templateStr, nil
+	}
+
+	type block struct {
+		enclosingActive bool // Whether the text around the block is kept
+		holds           bool
+		inElse          bool
+	}
+
+	var out strings.Builder
+	var blocks []block
+	active := true
+	pos := 0
+	for _, match := range matches {
+		if active {
+			out.WriteString(templateStr[pos:match[0]])
+		}
+		pos = match[1]
+
+		tag := templateStr[match[2]:match[3]]
+		switch {
+		case tag == "else":
+			if len(blocks) == 0 || blocks[len(blocks)-1].inElse {
+				return "", fmt.Errorf("unexpected {{else}} at offset %d", match[0])
+			}
+			top := &blocks[len(blocks)-1]
+			top.inElse = true
+			active = top.enclosingActive && !top.holds
+		case tag == "end":
+			if len(blocks) == 0 {
+				return "", fmt.Errorf("unexpected {{end}} at offset %d", match[0])
+			}
+			active = blocks[len(blocks)-1].enclosingActive
+			blocks = blocks[:len(blocks)-1]
+		default:
+			holds, err := evaluateBlockCondition(strings.TrimSpace(strings.TrimPrefix(tag, "if")), variables)
+			if err != nil {
+				return "", err
+			}
+			blocks = append(blocks, block{enclosingActive: active, holds: holds})
+			active = active && holds
+		}
+	}
+	if len(blocks) > 0 {
+		return "", fmt.Errorf("{{if}} without a matching {{end}}")
+	}
+
+	out.WriteString(templateStr[pos:])
+	return out.String(), nil
+}
+
+// evaluateBlockCondition evaluates the condition of an {{if}} tag
+func evaluateBlockCondition(condition string, variables map[string]interface{}) (bool, error) {
+	name, presence, ok := parseBlockCondition(condition)
+	if !ok {
+		return false, fmt.Errorf("invalid condition {{if %s}}, expected a variable name or hasVar \"name\"", condition)
+	}
+
+	value, set := variables[name]
+	if presence {
+		return set, nil
+	}
+	return set && !isEmptyValue(value), nil
+}
+
+// parseBlockCondition returns the variable an {{if}} condition tests and
+// whether it only asks for the variable's presence, as hasVar does
+func parseBlockCondition(condition string) (name string, presence bool, ok bool) {
+	fields := strings.Fields(condition)
+	if len(fields) == 2 && fields[0] == "hasVar" {
+		presence = true
+		fields = fields[1:]
+	}
+	if len(fields) != 1 {
+		return "", false, false
+	}
+
+	name = strings.TrimPrefix(strings.Trim(fields[0], `"`), ".")
+	return name, presence, name != ""
+}
+
+// templateReference returns the variable a {{...}} tag refers to: the name
+// of a placeholder, without its default, or the variable tested by an {{if}}
+// tag. {{else}} and {{end}} refer to none.
+func templateReference(tag string) (string, bool) {
+	switch {
+	case tag == "else" || tag == "end":
+		return "", false
+	case strings.HasPrefix(tag, "if ") || strings.HasPrefix(tag, "if\t"):
+		name, _, ok := parseBlockCondition(strings.TrimSpace(tag[2:]))
+		return name, ok
+	default:
+		name, _, _ := splitPlaceholder(tag)
+		return name, true
+	}
+}
+
+// conditionalWarnings reports malformed conditional blocks in a job's
+// bodies, body templates, headers and query parameters, such as a stray
+// {{else}}, a missing {{end}} or a bad condition, which would otherwise
+// only surface when a run renders them
+func conditionalWarnings(job config.CronJob) []string {
+	warnings := []string{}
+	check := func(field, text string) {
+		if _, err := renderConditionals(text, nil); err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", field, err))
+		}
+	}
+
+	check("default_body_template", job.DefaultBodyTemplate)
+	for _, w := range jobWebhooks(&job) {
+		check(w.name+".body", w.webhook.Body)
+		check(w.name+".body_template", w.webhook.BodyTemplate)
+		for _, name := range sortedKeys(keySet(w.webhook.Headers)) {
+			check(w.name+".headers."+name, w.webhook.Headers[name])
+		}
+		for _, name := range sortedKeys(keySet(w.webhook.QueryParams)) {
+			check(w.name+".query_params."+name, w.webhook.QueryParams[name])
+		}
+	}
+	return warnings
+}
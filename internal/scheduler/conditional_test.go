@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+
+	"cron-microservice/internal/config"
+)
+
+func TestRenderConditionals(t *testing.T) {
+	vars := map[string]interface{}{"token": "abc", "empty": "", "count": 0}
+	for _, tc := range []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"present", `{{if token}}"token": "{{token}}",{{end}}"x": 1`, `"token": "{{token}}","x": 1`},
+		{"absent", `{{if missing}}"m": 1,{{end}}"x": 1`, `"x": 1`},
+		{"empty", `{{if empty}}set{{else}}unset{{end}}`, `unset`},
+		{"dotted", `{{if .token}}set{{end}}`, `set`},
+		{"hasVar empty", `{{if hasVar "empty"}}set{{else}}unset{{end}}`, `set`},
+		{"hasVar missing", `{{if hasVar "missing"}}set{{else}}unset{{end}}`, `unset`},
+		{"else", `{{if missing}}a{{else}}b{{end}}`, `b`},
+		{"nested", `{{if token}}a{{if missing}}b{{else}}c{{end}}d{{end}}`, `acd`},
+		{"nested under absent", `{{if missing}}a{{if token}}b{{end}}{{else}}c{{end}}`, `c`},
+		{"no blocks", `{"x": "{{token}}"}`, `{"x": "{{token}}"}`},
+	} {
+		got, err := renderConditionals(tc.template, vars)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRenderConditionalsMalformed(t *testing.T) {
+	for name, template := range map[string]string{
+		"stray else":    `a{{else}}b`,
+		"double else":   `{{if token}}a{{else}}b{{else}}c{{end}}`,
+		"stray end":     `a{{end}}`,
+		"missing end":   `{{if token}}a`,
+		"bad condition": `{{if token == "x"}}a{{end}}`,
+	} {
+		if _, err := renderConditionals(template, nil); err == nil {
+			t.Errorf("%s: expected an error for %q", name, template)
+		}
+	}
+}
+
+func TestTemplateWarningsReportMalformedConditionals(t *testing.T) {
+	job := config.CronJob{
+		ID:       "job",
+		Schedule: "@every 1h",
+		Primary: config.WebhookConfig{
+			URL:     "http://example.com",
+			Method:  "POST",
+			Body:    `{{if job_id}}{"id": "{{job_id}}"}`,
+			Headers: map[string]string{"X-Token": `{{else}}`},
+		},
+		Secondary: &config.WebhookConfig{
+			URL:    "http://example.com",
+			Method: "POST",
+			Body:   `{{if job_id}}{"id": "{{job_id}}"}{{end}}`,
+		},
+	}
+
+	warnings := TemplateWarnings(job)
+	for _, field := range []string{"primary.body:", "primary.headers.X-Token:"} {
+		if !containsPrefix(warnings, field) {
+			t.Errorf("no warning for %s in %q", field, warnings)
+		}
+	}
+	if containsPrefix(warnings, "secondary.") {
+		t.Errorf("unexpected warning for the well-formed secondary body: %q", warnings)
+	}
+}
+
+func containsPrefix(values []string, prefix string) bool {
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
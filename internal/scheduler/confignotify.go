@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+// configNotifier coalesces bursts of config changes into a single
+// notification sent to the OnConfigChange webhook
+type configNotifier struct {
+	mu      sync.Mutex
+	pending map[string]string // job ID -> "added", "updated", or "removed"
+	timer   *time.Timer
+}
+
+// WatchConfigChanges sends the config's OnConfigChange webhook whenever a
+// save changes jobs. Changes within the debounce window are merged into one
+// notification, and sending happens in the background so saves never block.
+func (s *Scheduler) WatchConfigChanges(debounce time.Duration) {
+	notifier := &configNotifier{pending: make(map[string]string)}
+
+	s.config.OnChange(func(change config.Change) {
+		notifier.mu.Lock()
+		defer notifier.mu.Unlock()
+
+		for _, id := range change.Added {
+			notifier.pending[id] = "added"
+		}
+		for _, id := range change.Updated {
+			if notifier.pending[id] != "added" {
+				notifier.pending[id] = "updated"
+			}
+		}
+		for _, id := range change.Removed {
+			if notifier.pending[id] == "added" {
+				delete(notifier.pending, id)
+			} else {
+				notifier.pending[id] = "removed"
+			}
+		}
+
+		if notifier.timer == nil {
+			notifier.timer = time.AfterFunc(debounce, func() {
+				s.sendConfigChange(notifier.flush())
+			})
+		}
+	})
+}
+
+// flush returns the accumulated change and resets the notifier
+func (n *configNotifier) flush() config.Change {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	change := config.Change{Added: []string{}, Updated: []string{}, Removed: []string{}}
+	for id, kind := range n.pending {
+		switch kind {
+		case "added":
+			change.Added = append(change.Added, id)
+		case "updated":
+			change.Updated = append(change.Updated, id)
+		case "removed":
+			change.Removed = append(change.Removed, id)
+		}
+	}
+	sort.Strings(change.Added)
+	sort.Strings(change.Updated)
+	sort.Strings(change.Removed)
+
+	n.pending = make(map[string]string)
+	n.timer = nil
+	return change
+}
+
+// sendConfigChange posts a change summary to the OnConfigChange webhook.
+// Without a body or template the summary itself is sent as JSON; templates
+// can use {{added}}, {{updated}}, {{removed}} and {{summary}}.
+func (s *Scheduler) sendConfigChange(change config.Change) {
+	webhook := s.config.OnConfigChange
+	if webhook == nil || !webhook.Enabled || change.Empty() {
+		return
+	}
+
+	summary, err := json.Marshal(change)
+	if err != nil {
+		s.logger.Printf("[CONFIG_CHANGE_ERROR] Failed to marshal change summary: %v", err)
+		return
+	}
+
+	ctx := withStep(context.Background(), "config", "on_change")
+	notification := *webhook
+	variables := map[string]interface{}{
+		"added":   change.Added,
+		"updated": change.Updated,
+		"removed": change.Removed,
+		"summary": string(summary),
+	}
+
+	switch {
+	case notification.BodyTemplate != "":
+		body, err := s.renderTemplate(ctx, notification, notification.BodyTemplate, variables)
+		if err != nil {
+			s.logger.Printf("[CONFIG_CHANGE_ERROR] Failed to process template: %v", err)
+			return
+		}
+		notification.Body = body
+	case notification.Body == "":
+		notification.Body = string(summary)
+	}
+
+	if _, err := s.executeWebhook(ctx, notification); err != nil {
+		s.logger.Printf("[CONFIG_CHANGE_ERROR] Failed to send config change notification: %v", err)
+		return
+	}
+
+	s.logger.Printf("[CONFIG_CHANGE_SENT] Notified config change: %s", summary)
+}
@@ -0,0 +1,22 @@
+package scheduler
+
+// SetDefaultContentType controls whether requests with a body but no
+// configured Content-Type get one detected from the body. It is on by
+// default. When off, a request only carries a Content-Type set in its
+// headers or content_type, for endpoints that reject or misread a guessed
+// one.
+func (s *Scheduler) SetDefaultContentType(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.omitContentType = !enabled
+}
+
+// detectsContentType reports whether requests without a configured
+// Content-Type get a default one
+func (s *Scheduler) detectsContentType() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return !s.omitContentType
+}
@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cron-microservice/internal/config"
+)
+
+func TestContentType(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		detect      bool
+		body        string
+		contentType string
+		headers     map[string]string
+		want        string
+	}{
+		{name: "json detected", detect: true, body: `{"a": 1}`, want: "application/json"},
+		{name: "xml detected", detect: true, body: `<a/>`, want: "application/xml"},
+		{name: "text detected", detect: true, body: `hello`, want: "text/plain; charset=utf-8"},
+		{name: "content_type wins", detect: true, body: `{"a": 1}`, contentType: "text/plain", want: "text/plain"},
+		{name: "header wins", detect: true, body: `{"a": 1}`, contentType: "text/plain", headers: map[string]string{"Content-Type": "application/x-ndjson"}, want: "application/x-ndjson"},
+		{name: "no body", detect: true, want: ""},
+		{name: "detection off", body: `{"a": 1}`, want: ""},
+		{name: "detection off with content_type", body: `{"a": 1}`, contentType: "text/plain", want: "text/plain"},
+		{name: "detection off with header", body: `{"a": 1}`, headers: map[string]string{"Content-Type": "text/csv"}, want: "text/csv"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := make(chan string, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got <- r.Header.Get("Content-Type")
+			}))
+			defer server.Close()
+
+			job := config.CronJob{
+				ID:      "job",
+				Name:    "job",
+				Enabled: true,
+				Primary: config.WebhookConfig{
+					URL:         server.URL,
+					Method:      http.MethodPost,
+					Body:        tc.body,
+					ContentType: tc.contentType,
+					Headers:     tc.headers,
+					Enabled:     true,
+				},
+			}
+			s, _ := newTestScheduler(t, job)
+			s.SetDefaultContentType(tc.detect)
+
+			if r := s.executeJob(job, TriggerManual, nil); r.record.Status != StatusSuccess {
+				t.Fatalf("run status = %s (%s), want success", r.record.Status, r.record.Error)
+			}
+			if contentType := <-got; contentType != tc.want {
+				t.Errorf("Content-Type = %q, want %q", contentType, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"cron-microservice/internal/config"
+)
+
+// defaultBody returns a job's DefaultBodyTemplate for a webhook that has
+// neither a body nor a body template of its own, and "" otherwise. GET and
+// HEAD requests, including those with no method set, never get it.
+func defaultBody(job config.CronJob, webhook config.WebhookConfig) string {
+	if webhook.Body != "" || webhook.BodyTemplate != "" {
+		return ""
+	}
+	if webhook.Method == "" || strings.EqualFold(webhook.Method, http.MethodGet) || isHead(webhook.Method) {
+		return ""
+	}
+	return job.DefaultBodyTemplate
+}
+
+// withDefaultBodies returns a copy of the job whose primary and secondary
+// use its DefaultBodyTemplate as their body template where they have no
+// body of their own
+func withDefaultBodies(job config.CronJob) config.CronJob {
+	if job.DefaultBodyTemplate == "" {
+		return job
+	}
+
+	if body := defaultBody(job, job.Primary); body != "" {
+		job.Primary.BodyTemplate = body
+	}
+	if job.Secondary != nil {
+		if body := defaultBody(job, *job.Secondary); body != "" {
+			secondary := *job.Secondary
+			secondary.BodyTemplate = body
+			job.Secondary = &secondary
+		}
+	}
+	return job
+}
+
+// reminderFallbackBody is the secondary body of a reminder whose job sets
+// neither a secondary body nor a default body template, and whose primary
+// gave no response to forward
+func reminderFallbackBody(reminder config.Reminder) string {
+	body, _ := json.Marshal(map[string]string{"reminder": reminder.Text, "message": reminder.Text})
+	return string(body)
+}
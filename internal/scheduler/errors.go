@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// Webhook failure classes. Errors returned by executeWebhook wrap one of
+// these alongside the underlying error, so callers can use errors.Is to
+// tell them apart and errors.As to reach the cause.
+var (
+	ErrTimeout    = errors.New("webhook timed out")
+	ErrConnection = errors.New("webhook connection failed")
+	ErrStatus     = errors.New("webhook returned an error status")
+)
+
+// Error kinds recorded in Execution.ErrorKind
+const (
+	ErrorKindTimeout    = "timeout"
+	ErrorKindConnection = "connection"
+	ErrorKindStatus     = "status"
+	ErrorKindDenied     = "denied"
+	ErrorKindOther      = "other"
+)
+
+// ErrorKinds lists every error kind, in the order /metrics reports them
+var ErrorKinds = []string{ErrorKindTimeout, ErrorKindConnection, ErrorKindStatus, ErrorKindDenied, ErrorKindOther}
+
+// webhookError tags an error with its failure class
+type webhookError struct {
+	class error
+	err   error
+}
+
+func (e *webhookError) Error() string {
+	return e.err.Error()
+}
+
+func (e *webhookError) Unwrap() []error {
+	return []error{e.class, e.err}
+}
+
+// Is lets errors.Is(err, ErrStatus) match status errors
+func (e *statusError) Is(target error) bool {
+	return target == ErrStatus
+}
+
+// classifyTransportError tags an error from sending a request or reading
+// its response as a timeout or a connection failure
+func classifyTransportError(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrHostDenied) {
+		return err
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return &webhookError{class: ErrTimeout, err: err}
+	}
+	return &webhookError{class: ErrConnection, err: err}
+}
+
+// errorKind names the failure class of an error for the execution history
+func errorKind(err error) string {
+	switch {
+	case errors.Is(err, ErrHostDenied):
+		return ErrorKindDenied
+	case errors.Is(err, ErrTimeout):
+		return ErrorKindTimeout
+	case errors.Is(err, ErrConnection):
+		return ErrorKindConnection
+	case errors.Is(err, ErrStatus):
+		return ErrorKindStatus
+	default:
+		return ErrorKindOther
+	}
+}
@@ -0,0 +1,239 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"cron-microservice/internal/config"
+)
+
+// Explanation describes how a cron schedule will be interpreted
+type Explanation struct {
+	Schedule    string    `json:"schedule"`
+	Description string    `json:"description"`
+	Timezone    string    `json:"timezone"`
+	NextRun     time.Time `json:"next_run"`
+}
+
+// cronField describes one field of a five-field cron expression
+type cronField struct {
+	unit  string
+	names []string // Display names indexed by value, for months and weekdays
+}
+
+var (
+	minuteField  = cronField{unit: "minute"}
+	hourField    = cronField{unit: "hour"}
+	domField     = cronField{unit: "day-of-month"}
+	monthField   = cronField{unit: "month", names: []string{"", "January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}}
+	weekdayField = cronField{unit: "day-of-week", names: []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}}
+)
+
+// descriptors are the predefined schedules the cron parser accepts
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ExplainSchedule describes a cron schedule in English, such as "At 00:00
+// on Sunday (UTC)", along with the timezone it runs in and its next run
+func ExplainSchedule(schedule string) (Explanation, error) {
+	parsed, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return Explanation{}, fmt.Errorf("invalid schedule %q: %w", schedule, err)
+	}
+
+	// Split off a CRON_TZ= or TZ= prefix the way the cron parser does
+	spec := strings.TrimSpace(schedule)
+	loc := config.DefaultLocation()
+	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
+		name, rest, _ := strings.Cut(spec, " ")
+		_, name, _ = strings.Cut(name, "=")
+		if loc, err = time.LoadLocation(name); err != nil {
+			return Explanation{}, fmt.Errorf("invalid timezone in schedule %q: %w", schedule, err)
+		}
+		spec = strings.TrimSpace(rest)
+	}
+
+	timezone := loc.String()
+	if loc == time.Local {
+		abbreviation, _ := time.Now().Zone()
+		timezone = "local time, " + abbreviation
+	}
+
+	explanation := Explanation{
+		Schedule: schedule,
+		Timezone: timezone,
+		NextRun:  parsed.Next(time.Now().In(loc)),
+	}
+
+	if every, ok := strings.CutPrefix(spec, "@every "); ok {
+		interval, _ := time.ParseDuration(strings.TrimSpace(every))
+		explanation.Description = fmt.Sprintf("Every %v, counted from when the job was scheduled", interval)
+		return explanation, nil
+	}
+	if expanded, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = expanded
+	}
+
+	explanation.Description = fmt.Sprintf("%s (%s)", describeCron(strings.Fields(spec)), timezone)
+	return explanation, nil
+}
+
+// describeCron renders the five fields of a validated cron expression
+func describeCron(fields []string) string {
+	minute, hour, dom, month, weekday := fields[0], fields[1], fields[2], fields[3], fields[4]
+
+	var b strings.Builder
+	if times, ok := clockTimes(minute, hour); ok {
+		b.WriteString("At " + times)
+	} else {
+		if isWildcard(minute) {
+			b.WriteString("At every minute")
+		} else {
+			b.WriteString("At " + minuteField.describe(minute))
+		}
+		if !isWildcard(hour) {
+			b.WriteString(" past " + hourField.describe(hour))
+		}
+	}
+
+	switch {
+	case !isWildcard(dom) && !isWildcard(weekday):
+		// With both restricted, cron fires when either matches
+		b.WriteString(" on " + domField.describe(dom) + " or on " + weekdayField.describe(weekday))
+	case !isWildcard(dom):
+		b.WriteString(" on " + domField.describe(dom))
+	case !isWildcard(weekday):
+		b.WriteString(" on " + weekdayField.describe(weekday))
+	}
+
+	if !isWildcard(month) {
+		b.WriteString(" in " + monthField.describe(month))
+	}
+
+	return b.String()
+}
+
+// clockTimes renders a single minute with plain hours as times of day,
+// such as "09:00 and 17:00"
+func clockTimes(minute, hour string) (string, bool) {
+	m, err := strconv.Atoi(minute)
+	if err != nil {
+		return "", false
+	}
+
+	times := []string{}
+	for _, part := range strings.Split(hour, ",") {
+		h, err := strconv.Atoi(part)
+		if err != nil {
+			return "", false
+		}
+		times = append(times, fmt.Sprintf("%02d:%02d", h, m))
+	}
+	return joinList(times), true
+}
+
+func isWildcard(field string) bool {
+	return field == "*" || field == "?"
+}
+
+// describe renders a restricted cron field, such as "minute 0 and 30",
+// "every 2nd hour" or "every day-of-week from Monday through Friday"
+func (f cronField) describe(field string) string {
+	parts := strings.Split(field, ",")
+
+	values := []string{}
+	for _, part := range parts {
+		if strings.ContainsAny(part, "*?-/") {
+			break
+		}
+		values = append(values, f.name(part))
+	}
+	if len(values) == len(parts) {
+		if f.names != nil {
+			return joinList(values)
+		}
+		return f.unit + " " + joinList(values)
+	}
+
+	phrases := make([]string, 0, len(parts))
+	for _, part := range parts {
+		phrases = append(phrases, f.describePart(part))
+	}
+	return joinList(phrases)
+}
+
+// describePart renders one comma-separated element of a field
+func (f cronField) describePart(part string) string {
+	span, stepText, hasStep := strings.Cut(part, "/")
+
+	every := "every " + f.unit
+	if step, _ := strconv.Atoi(stepText); hasStep && step > 1 {
+		every = "every " + ordinal(step) + " " + f.unit
+	}
+
+	switch {
+	case isWildcard(span):
+		return every
+	case strings.Contains(span, "-"):
+		low, high, _ := strings.Cut(span, "-")
+		return fmt.Sprintf("%s from %s through %s", every, f.name(low), f.name(high))
+	case hasStep:
+		// "a/n" runs from a to the end of the field's range
+		return fmt.Sprintf("%s from %s", every, f.name(span))
+	case f.names != nil:
+		return f.name(span)
+	default:
+		return f.unit + " " + span
+	}
+}
+
+// name returns the display name of a field value, accepting both numbers
+// and the three-letter names the cron parser understands
+func (f cronField) name(value string) string {
+	if f.names == nil {
+		return value
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 && n < len(f.names) {
+		return f.names[n]
+	}
+	for _, name := range f.names {
+		if name != "" && strings.EqualFold(name[:3], value) {
+			return name
+		}
+	}
+	return value
+}
+
+// joinList joins items as "a", "a and b" or "a, b and c"
+func joinList(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// ordinal formats n as "1st", "2nd", "3rd", "4th" and so on
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// defaultMaxVariableSize caps an extracted variable's JSON size in bytes
+	defaultMaxVariableSize = 1 << 20
+
+	// maxJQResults is how many results of one selector are examined while
+	// looking for a usable value
+	maxJQResults = 1000
+)
+
+// jqTimeout bounds how long a single selector may run
+var jqTimeout = 5 * time.Second
+
+// SetMaxVariableSize caps the size in bytes of values extracted by jq
+// selectors. Larger strings are truncated; larger arrays and objects are
+// dropped. Zero or less removes the cap.
+func (s *Scheduler) SetMaxVariableSize(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.maxVariableSize = size
+}
+
+// limitVariable applies the variable size cap to an extracted value. It
+// returns the value to store, truncated if it is a string, and false when
+// the value is too large to keep.
+func (s *Scheduler) limitVariable(ctx context.Context, name string, value interface{}) (interface{}, bool) {
+	logger := s.logFor(ctx)
+	s.mu.RLock()
+	limit := s.maxVariableSize
+	s.mu.RUnlock()
+	if limit <= 0 {
+		return value, true
+	}
+
+	if str, ok := value.(string); ok {
+		if len(str) <= limit {
+			return str, true
+		}
+		// Cut on a rune boundary so the result stays valid UTF-8
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(str[cut]) {
+			cut--
+		}
+		truncated := str[:cut]
+		logger.Printf("[JQ_VALUE_TRUNCATED] Variable '%s' truncated from %d to %d bytes", name, len(str), len(truncated))
+		return truncated, true
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil || len(encoded) > limit {
+		logger.Printf("[JQ_VALUE_TOO_LARGE] Variable '%s' is %d bytes, over the %d byte limit; not set", name, len(encoded), limit)
+		return nil, false
+	}
+	return value, true
+}
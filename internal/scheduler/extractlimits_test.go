@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// shortJQTimeout lowers jqTimeout for the rest of the test
+func shortJQTimeout(t *testing.T) {
+	t.Helper()
+	previous := jqTimeout
+	jqTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { jqTimeout = previous })
+}
+
+// slowSelector runs far longer than the shortened jqTimeout
+const slowSelector = `[range(100000000)] | length`
+
+func TestExtractVariablesStopsAtTimeout(t *testing.T) {
+	shortJQTimeout(t)
+	s, logs := newTestScheduler(t)
+
+	start := time.Now()
+	vars, err := s.extractVariables(context.Background(), `{"id": 1}`, map[string]string{"slow": slowSelector, "id": ".id"})
+	if err != nil {
+		t.Fatalf("extractVariables: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("extractVariables took %v, want about %v", elapsed, jqTimeout)
+	}
+	if _, ok := vars["slow"]; ok {
+		t.Error("a selector that timed out produced a value")
+	}
+	if vars["id"] == nil {
+		t.Error("a selector after the one that timed out was not run")
+	}
+
+	output := logs.String()
+	if n := strings.Count(output, "[JQ_TIMEOUT]"); n != 1 {
+		t.Errorf("timeout logged %d times, want once:\n%s", n, output)
+	}
+	for _, tag := range []string{"[JQ_ERROR]", "[JQ_LIMIT]"} {
+		if strings.Contains(output, tag) {
+			t.Errorf("unexpected %s for a selector that timed out:\n%s", tag, output)
+		}
+	}
+}
+
+func TestEvaluateConditionTimesOut(t *testing.T) {
+	shortJQTimeout(t)
+
+	start := time.Now()
+	if _, err := evaluateCondition(`{}`, slowSelector+` > 0`); err == nil {
+		t.Error("expected an error for a condition that runs past jqTimeout")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("evaluateCondition took %v, want about %v", elapsed, jqTimeout)
+	}
+}
@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+// defaultEnabledCheckTTL is how long an enabled check's answer is reused
+// when the job sets no enabled_check_ttl
+const defaultEnabledCheckTTL = time.Minute
+
+// flagState is the cached answer of a job's enabled check
+type flagState struct {
+	enabled   bool
+	checkedAt time.Time
+}
+
+// enabledChecks caches enabled check answers per job
+type enabledChecks struct {
+	mu    sync.Mutex
+	byJob map[string]flagState
+}
+
+func newEnabledChecks() *enabledChecks {
+	return &enabledChecks{byJob: make(map[string]flagState)}
+}
+
+// forget drops a job's cached answer so the next tick asks again
+func (c *enabledChecks) forget(jobID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.byJob, jobID)
+}
+
+// enabledByFlag reports whether a scheduled tick of the job may run
+// according to its EnabledCheck. Answers are cached for the job's
+// EnabledCheckTTL. When the check fails the last known answer is kept;
+// without one the tick is skipped.
+func (s *Scheduler) enabledByFlag(job config.CronJob) bool {
+	if job.EnabledCheck == nil || !job.EnabledCheck.Enabled {
+		return true
+	}
+
+	ttl := time.Duration(job.EnabledCheckTTL) * time.Second
+	if ttl <= 0 {
+		ttl = defaultEnabledCheckTTL
+	}
+
+	s.enabledChecks.mu.Lock()
+	cached, known := s.enabledChecks.byJob[job.ID]
+	s.enabledChecks.mu.Unlock()
+	if known && time.Since(cached.checkedAt) < ttl {
+		return cached.enabled
+	}
+
+	enabled, err := s.checkFlag(job)
+	if err != nil {
+		s.logger.Printf("[JOB_FLAG_ERROR] Enabled check failed for job %s, keeping last known state (enabled: %v): %v", job.ID, known && cached.enabled, err)
+		return known && cached.enabled
+	}
+
+	s.enabledChecks.mu.Lock()
+	s.enabledChecks.byJob[job.ID] = flagState{enabled: enabled, checkedAt: time.Now()}
+	s.enabledChecks.mu.Unlock()
+
+	if known && enabled != cached.enabled {
+		s.logger.Printf("[JOB_FLAG_CHANGED] Enabled check for job %s now reports enabled: %v", job.ID, enabled)
+	}
+	return enabled
+}
+
+// checkFlag calls the job's EnabledCheck webhook. The flag is on when the
+// call succeeds and its ExpectJQ, if set, is truthy.
+func (s *Scheduler) checkFlag(job config.CronJob) (bool, error) {
+	ctx := withStep(context.Background(), job.ID, "enabled_check")
+	response, err := s.executeWebhook(ctx, s.renderRequest(ctx, *job.EnabledCheck, nil))
+	if err != nil {
+		return false, err
+	}
+
+	if job.EnabledCheck.ExpectJQ == "" {
+		return true, nil
+	}
+	return evaluateCondition(response, job.EnabledCheck.ExpectJQ)
+}
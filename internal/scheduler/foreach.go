@@ -0,0 +1,173 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/itchyny/gojq"
+
+	"cron-microservice/internal/config"
+)
+
+const (
+	// defaultForEachLimit caps how many items a ForEach secondary is sent
+	// for when the webhook sets no for_each_limit
+	defaultForEachLimit = 100
+
+	// defaultForEachConcurrency is how many per-item calls run at once when
+	// the webhook sets no for_each_concurrency
+	defaultForEachConcurrency = 4
+)
+
+// selectItems runs a ForEach selector against a JSON response and returns
+// the elements of the array it yields
+func selectItems(jsonData, selector string) ([]interface{}, error) {
+	query, err := gojq.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse for_each selector '%s': %w", selector, err)
+	}
+
+	var data interface{}
+	if err := json.Unmarshal([]byte(jsonData), &data); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), jqTimeout)
+	defer cancel()
+
+	v, ok := query.RunWithContext(ctx, data).Next()
+	if !ok || v == nil {
+		return nil, nil
+	}
+	if err, ok := v.(error); ok {
+		return nil, fmt.Errorf("failed to evaluate for_each selector '%s': %w", selector, err)
+	}
+
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("for_each selector '%s' returned %T, not an array", selector, v)
+	}
+	return items, nil
+}
+
+// itemScope copies scope and adds an item as {{item}}, with the fields of
+// an object item also available as {{item.<field>}}
+func itemScope(scope map[string]interface{}, item interface{}) map[string]interface{} {
+	variables := make(map[string]interface{}, len(scope)+1)
+	for k, v := range scope {
+		variables[k] = v
+	}
+
+	variables["item"] = item
+	if fields, ok := item.(map[string]interface{}); ok {
+		for k, v := range fields {
+			variables["item."+k] = v
+		}
+	}
+	return variables
+}
+
+// executeSecondaryForEach sends the secondary webhook once per element of
+// the array its ForEach selector picks from the primary response. Calls run
+// with bounded concurrency, items past the limit are dropped, and without a
+// body template each call's body is the item as JSON.
+func (s *Scheduler) executeSecondaryForEach(ctx context.Context, job config.CronJob, response string, scope map[string]interface{}) {
+	logger := s.logFor(ctx)
+	run := runFrom(ctx)
+	secondary := *job.Secondary
+
+	// Selector variables come from the same response and apply to every call
+	if len(secondary.JQSelectors) > 0 {
+		extractionStart := time.Now()
+		vars, err := s.extractVariables(ctx, response, secondary.JQSelectors)
+		run.timing(func(t *Timing) { t.ExtractionMs = milliseconds(time.Since(extractionStart)) })
+		if err != nil {
+			logger.Printf("[JQ_ERROR] Failed to extract variables: %v", err)
+		} else {
+			mergeVariables(scope, vars)
+		}
+	}
+
+	items, err := selectItems(response, secondary.ForEach)
+	if err != nil {
+		run.fail(err)
+		logger.Printf("[SECONDARY_FOREACH_ERROR] Failed to select items for job %s: %v", job.ID, err)
+		return
+	}
+	if len(items) == 0 {
+		logger.Printf("[SECONDARY_FOREACH_EMPTY] No items selected by '%s' for job %s", secondary.ForEach, job.ID)
+		return
+	}
+
+	limit := secondary.ForEachLimit
+	if limit <= 0 {
+		limit = defaultForEachLimit
+	}
+	if len(items) > limit {
+		logger.Printf("[SECONDARY_FOREACH_LIMIT] Job %s selected %d items, sending only the first %d", job.ID, len(items), limit)
+		items = items[:limit]
+	}
+
+	concurrency := secondary.ForEachConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultForEachConcurrency
+	}
+
+	logger.Printf("[SECONDARY_FOREACH] Sending %s request to %s for %d items with concurrency %d", secondary.Method, secondary.URL, len(items), concurrency)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failed := 0
+	sem := make(chan struct{}, concurrency)
+	secondaryStart := time.Now()
+
+	for i, item := range items {
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, item interface{}) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			variables := itemScope(scope, item)
+			call := secondary
+			if call.BodyTemplate != "" {
+				body, err := s.renderTemplate(ctx, call, call.BodyTemplate, variables)
+				if err != nil {
+					logger.Printf("[TEMPLATE_ERROR] Failed to process template for item %d: %v", i, err)
+				} else {
+					call.Body = body
+				}
+			} else {
+				body, _ := json.Marshal(item)
+				call.Body = string(body)
+			}
+
+			if s.skipForEmptyVariables(ctx, job.ID, call, variables) {
+				return
+			}
+
+			if _, err := s.executeWebhook(withStep(ctx, job.ID, "secondary"), s.renderRequest(ctx, call, variables)); err != nil {
+				run.fail(err)
+				mu.Lock()
+				failed++
+				mu.Unlock()
+				logger.Printf("[SECONDARY_WEBHOOK_ERROR] Failed to execute secondary webhook for item %d of job %s: %v", i, job.ID, err)
+			}
+		}(i, item)
+	}
+	wg.Wait()
+
+	run.timing(func(t *Timing) { t.SecondaryMs = milliseconds(time.Since(secondaryStart)) })
+	if failed > 0 {
+		logger.Printf("[SECONDARY_FOREACH_DONE] %d of %d items failed for job %s", failed, len(items), job.ID)
+	} else {
+		logger.Printf("[SECONDARY_FOREACH_DONE] Secondary webhook executed for %d items of job %s", len(items), job.ID)
+	}
+}
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"cron-microservice/internal/config"
+)
+
+// placeholderPattern matches {{name}} template placeholders, including
+// ones with a default like {{name:-default}}
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+// headerLineBreaks removes the characters that would end a header line
+var headerLineBreaks = strings.NewReplacer("\r", "", "\n", "")
+
+// renderHeaders substitutes variables into a webhook's header values as
+// plain text. When DropEmptyHeaders is set, a header whose placeholders
+// refer to a missing or empty variable is removed instead of being sent
+// with a blank value.
+func (s *Scheduler) renderHeaders(ctx context.Context, webhook config.WebhookConfig, scope map[string]interface{}) config.WebhookConfig {
+	logger := s.logFor(ctx)
+	if len(webhook.Headers) == 0 {
+		return webhook
+	}
+
+	headers := make(map[string]string, len(webhook.Headers))
+	for key, value := range webhook.Headers {
+		placeholders := placeholderPattern.FindAllStringSubmatch(value, -1)
+		if len(placeholders) == 0 {
+			headers[key] = value
+			continue
+		}
+
+		if webhook.DropEmptyHeaders && hasEmptyVariable(placeholders, scope) {
+			logger.Printf("[WEBHOOK_HEADER_DROPPED] Dropping header %s because a referenced variable is empty", key)
+			continue
+		}
+
+		rendered, stripped, err := renderHeaderValue(value, scope)
+		if err != nil {
+			logger.Printf("[WEBHOOK_HEADER_ERROR] Failed to process header %s: %v", key, err)
+			rendered = value
+		}
+		if stripped {
+			logger.Printf("[WEBHOOK_HEADER_SANITIZED] Stripped line breaks from a variable in header %s", key)
+		}
+
+		if webhook.DropEmptyHeaders && rendered == "" {
+			logger.Printf("[WEBHOOK_HEADER_DROPPED] Dropping header %s because it rendered empty", key)
+			continue
+		}
+		headers[key] = rendered
+	}
+
+	webhook.Headers = headers
+	return webhook
+}
+
+// renderHeaderValue renders a header template. Variables are written as
+// plain text, not escaped for JSON as in bodies, and CR and LF are stripped
+// from them so a variable can't split the header or add another. stripped
+// reports whether any were.
+func renderHeaderValue(value string, scope map[string]interface{}) (rendered string, stripped bool, err error) {
+	rendered, err = renderConditionals(value, scope)
+	if err != nil {
+		return "", false, err
+	}
+
+	rendered = placeholderPattern.ReplaceAllStringFunc(rendered, func(placeholder string) string {
+		value, _ := resolvePlaceholder(placeholderPattern.FindStringSubmatch(placeholder)[1], scope)
+		text := stringifyValue(value)
+		if clean := headerLineBreaks.Replace(text); clean != text {
+			stripped = true
+			return clean
+		}
+		return text
+	})
+	return rendered, stripped, nil
+}
+
+// hasEmptyVariable reports whether any placeholder refers to a variable
+// that is missing or empty and has no default to fall back to
+func hasEmptyVariable(placeholders [][]string, scope map[string]interface{}) bool {
+	for _, match := range placeholders {
+		if value, _ := resolvePlaceholder(match[1], scope); isEmptyValue(value) {
+			return true
+		}
+	}
+	return false
+}
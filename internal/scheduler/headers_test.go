@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cron-microservice/internal/config"
+)
+
+func TestRenderHeadersDropEmpty(t *testing.T) {
+	headers := map[string]string{
+		"Authorization": "Bearer {{token}}",
+		"X-Tenant":      "{{tenant}}",
+		"X-Region":      "{{region:-eu}}",
+		"X-Zone":        "{{zone:-}}",
+		"X-Static":      "fixed",
+		"X-Tags":        "{{tags}}",
+	}
+	scope := map[string]interface{}{"token": "abc", "tenant": "", "tags": []interface{}{}}
+
+	for _, tt := range []struct {
+		name string
+		drop bool
+		want map[string]string
+	}{
+		{"drop", true, map[string]string{
+			"Authorization": "Bearer abc",
+			"X-Region":      "eu",
+			"X-Static":      "fixed",
+		}},
+		{"keep", false, map[string]string{
+			"Authorization": "Bearer abc",
+			"X-Tenant":      "",
+			"X-Region":      "eu",
+			"X-Zone":        "",
+			"X-Static":      "fixed",
+			"X-Tags":        "[]",
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, logs := newTestScheduler(t)
+			webhook := config.WebhookConfig{Headers: headers, DropEmptyHeaders: tt.drop}
+
+			rendered := s.renderHeaders(context.Background(), webhook, scope)
+			if !reflect.DeepEqual(rendered.Headers, tt.want) {
+				t.Errorf("headers %v, want %v", rendered.Headers, tt.want)
+			}
+			if dropped := strings.Count(logs.String(), "[WEBHOOK_HEADER_DROPPED]"); dropped != len(headers)-len(tt.want) {
+				t.Errorf("%d headers logged as dropped, want %d:\n%s", dropped, len(headers)-len(tt.want), logs.String())
+			}
+			if webhook.Headers["X-Tenant"] != "{{tenant}}" {
+				t.Error("renderHeaders changed the webhook's own headers")
+			}
+		})
+	}
+}
+
+func TestRenderHeadersPlainText(t *testing.T) {
+	s, logs := newTestScheduler(t)
+	webhook := config.WebhookConfig{Headers: map[string]string{
+		"X-Title":  `{{title}}`,
+		"X-Count":  "n={{count}}",
+		"X-Inject": "{{name}}",
+	}}
+	scope := map[string]interface{}{
+		"title": "say \"hi\"\tnow",
+		"count": json.Number("1.50"),
+		"name":  "bob\r\nX-Admin: true",
+	}
+
+	rendered := s.renderHeaders(context.Background(), webhook, scope)
+	want := map[string]string{
+		"X-Title":  "say \"hi\"\tnow",
+		"X-Count":  "n=1.50",
+		"X-Inject": "bobX-Admin: true",
+	}
+	if !reflect.DeepEqual(rendered.Headers, want) {
+		t.Errorf("headers %q, want %q", rendered.Headers, want)
+	}
+	if n := strings.Count(logs.String(), "[WEBHOOK_HEADER_SANITIZED]"); n != 1 {
+		t.Errorf("line breaks stripped from %d headers, want 1:\n%s", n, logs.String())
+	}
+}
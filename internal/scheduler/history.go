@@ -0,0 +1,240 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/tls"
+	"math"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// defaultHistorySize is how many executions are kept per job
+const defaultHistorySize = 100
+
+// Execution triggers
+const (
+	TriggerSchedule       = "schedule"
+	TriggerManual         = "manual"
+	TriggerReminder       = "reminder"
+	TriggerInvoke         = "invoke"
+	TriggerRerunSecondary = "rerun-secondary"
+	TriggerReminderRetry  = "reminder-retry"
+)
+
+// Execution outcomes
+const (
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+	StatusSkipped = "skipped"
+)
+
+// Execution records the outcome of a single job or reminder run
+type Execution struct {
+	ID         string    `json:"id"`
+	JobID      string    `json:"job_id"`
+	Trigger    string    `json:"trigger"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs float64   `json:"duration_ms"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	ErrorKind  string    `json:"error_kind,omitempty"` // timeout, connection, status or other
+	Reason     string    `json:"reason,omitempty"`     // Why a run was skipped
+	Timing     Timing    `json:"timing"`
+}
+
+// Timing breaks an execution's duration down by step. DNS, connect and TLS
+// times are summed over every request made during the run.
+type Timing struct {
+	DNSMs        float64 `json:"dns_ms"`
+	ConnectMs    float64 `json:"connect_ms"`
+	TLSMs        float64 `json:"tls_ms"`
+	PrecheckMs   float64 `json:"precheck_ms,omitempty"`
+	PrimaryMs    float64 `json:"primary_ms"`
+	ExtractionMs float64 `json:"extraction_ms"`
+	SecondaryMs  float64 `json:"secondary_ms"`
+}
+
+// run is an execution in progress. Its record may be updated from HTTP
+// trace callbacks, so all changes go through its mutex.
+type run struct {
+	mu        sync.Mutex
+	record    Execution
+	output    string         // Primary webhook response
+	sensitive []string       // The job's sensitive_variables
+	secrets   []string       // Values masked in the run's log lines, longest first
+	replaced  bool           // Cancelled by a newer tick under the replace policy
+	computed  computedValues // Last computed variables, reused by the next render of the same step
+}
+
+// fail marks the run as failed, keeping the first error seen
+func (r *run) fail(err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Errors from cancelling a replaced run are not failures of the job
+	if r.replaced {
+		return
+	}
+	if r.record.Status != StatusFailure {
+		r.record.Status = StatusFailure
+		r.record.Error = r.maskLocked(err.Error())
+		r.record.ErrorKind = errorKind(err)
+	}
+}
+
+// skip marks the run as skipped with a reason
+func (r *run) skip(reason string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.record.Status = StatusSkipped
+	r.record.Reason = reason
+}
+
+// timing applies an update to the run's timing breakdown
+func (r *run) timing(update func(*Timing)) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	update(&r.record.Timing)
+}
+
+// history keeps the most recent executions of each job
+type history struct {
+	mu    sync.RWMutex
+	limit int
+	byJob map[string][]Execution
+}
+
+func newHistory(limit int) *history {
+	return &history{limit: limit, byJob: make(map[string][]Execution)}
+}
+
+func (h *history) record(e Execution) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	executions := append(h.byJob[e.JobID], e)
+	if len(executions) > h.limit {
+		executions = executions[len(executions)-h.limit:]
+	}
+	h.byJob[e.JobID] = executions
+}
+
+// forJob returns a job's executions, newest first
+func (h *history) forJob(jobID string) []Execution {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	executions := h.byJob[jobID]
+	result := make([]Execution, len(executions))
+	for i, e := range executions {
+		result[len(executions)-1-i] = e
+	}
+	return result
+}
+
+func (h *history) forget(jobID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.byJob, jobID)
+}
+
+// History returns the recorded executions of a job, newest first
+func (s *Scheduler) History(jobID string) []Execution {
+	return s.history.forJob(jobID)
+}
+
+// startExecution begins recording a run of a job
+func (s *Scheduler) startExecution(jobID, trigger string) *run {
+	s.counters.inFlight.Add(1)
+	return &run{record: Execution{
+		ID:        newRunID(),
+		JobID:     jobID,
+		Trigger:   trigger,
+		StartedAt: time.Now(),
+		Status:    StatusSuccess,
+	}}
+}
+
+// finishExecution stamps the run's duration and stores it in the history
+func (s *Scheduler) finishExecution(r *run) {
+	r.mu.Lock()
+	r.record.DurationMs = milliseconds(time.Since(r.record.StartedAt))
+	record := r.record
+	r.mu.Unlock()
+
+	s.history.record(record)
+	s.counters.countFinished(record.Status)
+}
+
+// traceRequest attaches an httptrace.ClientTrace that adds DNS, connect and
+// TLS handshake durations to the run carried by ctx
+func traceRequest(ctx context.Context) context.Context {
+	r := runFrom(ctx)
+	if r == nil {
+		return ctx
+	}
+
+	var dnsStart, connectStart, tlsStart time.Time
+	var startMu sync.Mutex
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			startMu.Lock()
+			dnsStart = time.Now()
+			startMu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			startMu.Lock()
+			elapsed := time.Since(dnsStart)
+			startMu.Unlock()
+			r.timing(func(t *Timing) { t.DNSMs = addMilliseconds(t.DNSMs, elapsed) })
+		},
+		ConnectStart: func(string, string) {
+			startMu.Lock()
+			connectStart = time.Now()
+			startMu.Unlock()
+		},
+		ConnectDone: func(string, string, error) {
+			startMu.Lock()
+			elapsed := time.Since(connectStart)
+			startMu.Unlock()
+			r.timing(func(t *Timing) { t.ConnectMs = addMilliseconds(t.ConnectMs, elapsed) })
+		},
+		TLSHandshakeStart: func() {
+			startMu.Lock()
+			tlsStart = time.Now()
+			startMu.Unlock()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			startMu.Lock()
+			elapsed := time.Since(tlsStart)
+			startMu.Unlock()
+			r.timing(func(t *Timing) { t.TLSMs = addMilliseconds(t.TLSMs, elapsed) })
+		},
+	}
+
+	return httptrace.WithClientTrace(ctx, trace)
+}
+
+// milliseconds converts a duration to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// addMilliseconds adds a duration to a millisecond total, rounding to the
+// microsecond to avoid float noise in repeated sums
+func addMilliseconds(total float64, d time.Duration) float64 {
+	return math.Round((total+milliseconds(d))*1000) / 1000
+}
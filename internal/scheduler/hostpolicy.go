@@ -0,0 +1,252 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrHostDenied is wrapped by errors for requests the host policy refuses.
+// Denied requests are never sent and never retried.
+var ErrHostDenied = errors.New("webhook target host denied")
+
+// metadataNetworks are blocked unless link-local targets are allowed: the
+// link-local ranges, which hold the cloud metadata address 169.254.169.254,
+// plus metadata addresses outside them
+var metadataNetworks = mustParseCIDRs(
+	"169.254.0.0/16",
+	"fe80::/10",
+	"fd00:ec2::254/128",
+	"100.100.100.200/32",
+)
+
+// HostPolicy restricts which hosts webhooks may call. Rules are host names,
+// "*.example.com" wildcards matching subdomains, IP addresses, or CIDR
+// ranges.
+type HostPolicy struct {
+	allowNames     []string
+	allowNets      []*net.IPNet
+	denyNames      []string
+	denyNets       []*net.IPNet
+	allowLinkLocal bool
+}
+
+// NewHostPolicy builds a policy from allow and deny rules. A non-empty
+// allowlist permits only matching hosts, the denylist always wins, and
+// link-local and metadata addresses are refused unless allowLinkLocal is
+// set or an allow rule names them explicitly.
+func NewHostPolicy(allow, deny []string, allowLinkLocal bool) (*HostPolicy, error) {
+	policy := &HostPolicy{allowLinkLocal: allowLinkLocal}
+
+	var err error
+	if policy.allowNames, policy.allowNets, err = parseHostRules(allow); err != nil {
+		return nil, fmt.Errorf("invalid allowed host: %w", err)
+	}
+	if policy.denyNames, policy.denyNets, err = parseHostRules(deny); err != nil {
+		return nil, fmt.Errorf("invalid denied host: %w", err)
+	}
+	return policy, nil
+}
+
+// parseHostRules splits rules into lowercased names and networks, treating
+// a bare IP as a single-address network
+func parseHostRules(rules []string) ([]string, []*net.IPNet, error) {
+	var names []string
+	var nets []*net.IPNet
+	for _, rule := range rules {
+		rule = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(rule), "."))
+		switch {
+		case rule == "":
+			continue
+		case strings.Contains(rule, "/"):
+			_, network, err := net.ParseCIDR(rule)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%q is not a valid CIDR range", rule)
+			}
+			nets = append(nets, network)
+		case net.ParseIP(rule) != nil:
+			ip := net.ParseIP(rule)
+			bits := 8 * len(ip)
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		default:
+			names = append(names, rule)
+		}
+	}
+	return names, nets, nil
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, network)
+	}
+	return nets
+}
+
+// SetHostPolicy enforces a host policy on every webhook request, both
+// before dispatch and on every connection the HTTP clients dial
+func (s *Scheduler) SetHostPolicy(policy *HostPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hostPolicy = policy
+
+	transport := newTransport()
+	transport.DialContext = s.dialer(nil, 0)
+	s.httpClient = &http.Client{
+		Timeout:   s.httpClient.Timeout,
+		Transport: transport,
+	}
+}
+
+// checkURL applies the host policy to a webhook URL before it is sent. Host
+// names are checked by name here; their addresses are checked when dialed.
+func (p *HostPolicy) checkURL(rawURL string) error {
+	if p == nil {
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		// Left for the request itself to reject
+		return nil
+	}
+
+	host := u.Hostname()
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	}
+	return p.check(host, ips, ips != nil)
+}
+
+// check decides whether host may be called at the given addresses. When the
+// addresses are not yet known, an allowlist of networks defers to the dial.
+func (p *HostPolicy) check(host string, ips []net.IP, resolved bool) error {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	if matchHostName(p.denyNames, host) {
+		return fmt.Errorf("%w: %s is on the denylist", ErrHostDenied, host)
+	}
+
+	for _, ip := range ips {
+		if containsIP(p.denyNets, ip) {
+			return fmt.Errorf("%w: %s is on the denylist", ErrHostDenied, describeHost(host, ip))
+		}
+		if !p.allowLinkLocal && containsIP(metadataNetworks, ip) && !containsIP(p.allowNets, ip) {
+			return fmt.Errorf("%w: %s is a link-local or metadata address (use -allow-link-local to permit)", ErrHostDenied, describeHost(host, ip))
+		}
+	}
+
+	if len(p.allowNames) == 0 && len(p.allowNets) == 0 {
+		return nil
+	}
+	if matchHostName(p.allowNames, host) {
+		return nil
+	}
+	if !resolved {
+		if len(p.allowNets) > 0 {
+			return nil
+		}
+		return fmt.Errorf("%w: %s is not on the allowlist", ErrHostDenied, host)
+	}
+	for _, ip := range ips {
+		if !containsIP(p.allowNets, ip) {
+			return fmt.Errorf("%w: %s is not on the allowlist", ErrHostDenied, describeHost(host, ip))
+		}
+	}
+	return nil
+}
+
+// dial resolves addr, checks the host and every address against the policy,
+// and connects to the first address that accepts. requested is the address
+// the request was made for, which differs from addr under resolve_to: its
+// host name is checked together with the mapped addresses, so a host
+// allowed by name stays allowed, and a mapped host name must pass as well.
+func (p *HostPolicy) dial(ctx context.Context, dialer *net.Dialer, network, requested, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{host}
+	if requestedHost, _, err := net.SplitHostPort(requested); err == nil && requestedHost != host {
+		if net.ParseIP(host) != nil {
+			names = []string{requestedHost}
+		} else {
+			names = append(names, requestedHost)
+		}
+	}
+
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range addrs {
+			ips = append(ips, a.IP)
+		}
+	}
+
+	for _, name := range names {
+		if err := p.check(name, ips, true); err != nil {
+			return nil, err
+		}
+	}
+
+	lastErr := fmt.Errorf("no addresses found for %s", host)
+	for _, ip := range ips {
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// describeHost names a host in errors, adding the address it resolved to
+func describeHost(host string, ip net.IP) string {
+	if net.ParseIP(host) != nil {
+		return host
+	}
+	return fmt.Sprintf("%s (%s)", host, ip)
+}
+
+// matchHostName reports whether host equals a name or falls under a
+// "*.domain" wildcard
+func matchHostName(names []string, host string) bool {
+	for _, name := range names {
+		if suffix, ok := strings.CutPrefix(name, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, network := range nets {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
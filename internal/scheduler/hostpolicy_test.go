@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDialChecksRequestedHostWithMappedAddress(t *testing.T) {
+	server := httptest.NewServer(nil)
+	defer server.Close()
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	requested := net.JoinHostPort("api.example.com", port)
+
+	for _, tc := range []struct {
+		name    string
+		deny    []string
+		target  string
+		allowed bool
+	}{
+		{"allowed by name", nil, server.Listener.Addr().String(), true},
+		{"mapped address denied", []string{"127.0.0.0/8"}, server.Listener.Addr().String(), false},
+		{"mapped to metadata", nil, "169.254.169.254:80", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := NewHostPolicy([]string{"api.example.com"}, tc.deny, false)
+			if err != nil {
+				t.Fatalf("NewHostPolicy: %v", err)
+			}
+			s, _ := newTestScheduler(t)
+			s.SetHostPolicy(policy)
+
+			dial := s.dialer(map[string]string{requested: tc.target}, 0)
+			conn, err := dial(context.Background(), "tcp", requested)
+			if conn != nil {
+				conn.Close()
+			}
+			if tc.allowed && err != nil {
+				t.Errorf("dial: %v", err)
+			}
+			if !tc.allowed && !errors.Is(err, ErrHostDenied) {
+				t.Errorf("dial: err = %v, want ErrHostDenied", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cron-microservice/internal/config"
+)
+
+var weekdays = map[string]int{
+	"sunday":    0,
+	"monday":    1,
+	"tuesday":   2,
+	"wednesday": 3,
+	"thursday":  4,
+	"friday":    5,
+	"saturday":  6,
+}
+
+// ParseInterval converts a human-friendly interval such as "every 5 minutes"
+// or "daily at 09:00" into a cron expression. A step that doesn't divide
+// its period evenly, like "every 7 minutes", becomes an @every schedule,
+// since */7 would restart at every hour and leave a short gap.
+func ParseInterval(interval string) (string, error) {
+	fields := strings.Fields(strings.ToLower(strings.TrimSpace(interval)))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("interval is empty")
+	}
+
+	// Split off an optional trailing "at HH:MM"
+	minute, hour := 0, 0
+	hasTime := false
+	for i, field := range fields {
+		if field != "at" {
+			continue
+		}
+		if i != len(fields)-2 {
+			return "", fmt.Errorf("invalid interval %q: expected \"at HH:MM\" at the end", interval)
+		}
+		h, m, err := parseTimeOfDay(fields[i+1])
+		if err != nil {
+			return "", fmt.Errorf("invalid interval %q: %w", interval, err)
+		}
+		hour, minute, hasTime = h, m, true
+		fields = fields[:i]
+		break
+	}
+
+	if len(fields) > 0 && fields[0] == "every" {
+		fields = fields[1:]
+	}
+
+	switch {
+	case len(fields) == 1 && fields[0] == "minute":
+		if hasTime {
+			break
+		}
+		return "* * * * *", nil
+
+	case len(fields) == 1 && (fields[0] == "hour" || fields[0] == "hourly"):
+		if hasTime {
+			break
+		}
+		return "0 * * * *", nil
+
+	case len(fields) == 2 && isNumber(fields[0]):
+		n, _ := strconv.Atoi(fields[0])
+		if hasTime {
+			break
+		}
+		switch strings.TrimSuffix(fields[1], "s") {
+		case "minute", "min":
+			if n < 1 || n > 59 {
+				return "", fmt.Errorf("invalid interval %q: minutes must be between 1 and 59", interval)
+			}
+			if 60%n != 0 {
+				return fmt.Sprintf("@every %dm", n), nil
+			}
+			return fmt.Sprintf("*/%d * * * *", n), nil
+		case "hour":
+			if n < 1 || n > 23 {
+				return "", fmt.Errorf("invalid interval %q: hours must be between 1 and 23", interval)
+			}
+			if 24%n != 0 {
+				return fmt.Sprintf("@every %dh", n), nil
+			}
+			return fmt.Sprintf("0 */%d * * *", n), nil
+		case "day":
+			if n < 1 || n > 31 {
+				return "", fmt.Errorf("invalid interval %q: days must be between 1 and 31", interval)
+			}
+			// Months differ in length, so only daily lines up with them
+			if n > 1 {
+				return fmt.Sprintf("@every %dh", 24*n), nil
+			}
+			return "0 0 * * *", nil
+		}
+
+	case len(fields) == 1 && (fields[0] == "day" || fields[0] == "daily"):
+		return fmt.Sprintf("%d %d * * *", minute, hour), nil
+
+	case len(fields) == 1 && (fields[0] == "weekday" || fields[0] == "weekdays"):
+		return fmt.Sprintf("%d %d * * 1-5", minute, hour), nil
+
+	case len(fields) == 1 && (fields[0] == "weekend" || fields[0] == "weekends"):
+		return fmt.Sprintf("%d %d * * 0,6", minute, hour), nil
+
+	case len(fields) == 1 && (fields[0] == "week" || fields[0] == "weekly"):
+		return fmt.Sprintf("%d %d * * 0", minute, hour), nil
+
+	case len(fields) == 1 && (fields[0] == "month" || fields[0] == "monthly"):
+		return fmt.Sprintf("%d %d 1 * *", minute, hour), nil
+
+	case len(fields) == 1:
+		if day, ok := weekdays[strings.TrimSuffix(fields[0], "s")]; ok {
+			return fmt.Sprintf("%d %d * * %d", minute, hour, day), nil
+		}
+	}
+
+	return "", fmt.Errorf("unrecognized interval %q", interval)
+}
+
+// ResolveSchedule fills job.Schedule from job.Interval when no cron
+// expression is set. An explicit Schedule always takes precedence.
+func ResolveSchedule(job *config.CronJob) error {
+	if job.Schedule != "" || job.Interval == "" {
+		return nil
+	}
+
+	schedule, err := ParseInterval(job.Interval)
+	if err != nil {
+		return err
+	}
+
+	job.Schedule = schedule
+	return nil
+}
+
+// parseTimeOfDay parses "HH:MM" (24-hour) into hour and minute
+func parseTimeOfDay(value string) (int, int, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 || !isNumber(parts[0]) || !isNumber(parts[1]) {
+		return 0, 0, fmt.Errorf("time %q must be in HH:MM format", value)
+	}
+
+	hour, _ := strconv.Atoi(parts[0])
+	minute, _ := strconv.Atoi(parts[1])
+	if hour > 23 || minute > 59 {
+		return 0, 0, fmt.Errorf("time %q is out of range", value)
+	}
+
+	return hour, minute, nil
+}
+
+func isNumber(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,27 @@
+package scheduler
+
+import "testing"
+
+func TestParseIntervalSteps(t *testing.T) {
+	for _, tc := range []struct {
+		interval string
+		want     string
+	}{
+		{"every 5 minutes", "*/5 * * * *"},
+		{"every 7 minutes", "@every 7m"},
+		{"every 45 minutes", "@every 45m"},
+		{"every 6 hours", "0 */6 * * *"},
+		{"every 5 hours", "@every 5h"},
+		{"every 1 day", "0 0 * * *"},
+		{"every 3 days", "@every 72h"},
+	} {
+		got, err := ParseInterval(tc.interval)
+		if err != nil {
+			t.Errorf("ParseInterval(%q): %v", tc.interval, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("ParseInterval(%q) = %q, want %q", tc.interval, got, tc.want)
+		}
+	}
+}
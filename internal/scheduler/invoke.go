@@ -0,0 +1,30 @@
+package scheduler
+
+// InvokeResult is the outcome of a synchronous job invocation
+type InvokeResult struct {
+	Execution Execution `json:"execution"`
+	Response  string    `json:"response,omitempty"` // Primary webhook response
+}
+
+// Invoke runs a job once, synchronously, with vars seeded into its
+// variable scope. It is how job templates are run, but works for any job.
+// Invocations are recorded in the history but never count towards
+// auto-disable.
+func (s *Scheduler) Invoke(jobID string, vars map[string]interface{}) (InvokeResult, error) {
+	job, err := s.config.GetJob(jobID)
+	if err != nil {
+		return InvokeResult{}, err
+	}
+
+	release, err := s.acquireManualRun()
+	if err != nil {
+		return InvokeResult{}, err
+	}
+	defer release()
+
+	r := s.executeJob(*job, TriggerInvoke, vars)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return InvokeResult{Execution: r.record, Response: r.output}, nil
+}
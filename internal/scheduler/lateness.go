@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"cron-microservice/internal/config"
+)
+
+// addCronEntry adds a cron entry running a job's scheduled action on spec.
+// Cron doesn't tell the action when its tick was due, so for a job with a
+// MaxLateness the entry tracks that itself. The caller must hold s.mu.
+func (s *Scheduler) addCronEntry(job config.CronJob, spec string) (cron.EntryID, error) {
+	limit, err := job.LatenessLimit()
+	if err != nil {
+		return 0, err
+	}
+	if limit == 0 {
+		return s.cron.AddFunc(spec, s.scheduledAction(job, nil))
+	}
+
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return 0, err
+	}
+	return s.cron.Schedule(schedule, cron.FuncJob(s.scheduledAction(job, dueTimes(schedule)))), nil
+}
+
+// dueTimes returns a function to call once per tick of schedule, returning
+// when that tick was due. Like cron, it takes each tick to be the
+// schedule's next time after the previous tick started.
+func dueTimes(schedule cron.Schedule) func() time.Time {
+	var mu sync.Mutex
+	next := schedule.Next(time.Now().In(config.DefaultLocation()))
+	return func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+
+		due := next
+		next = schedule.Next(time.Now().In(config.DefaultLocation()))
+		return due
+	}
+}
+
+// tooLate reports whether a tick due at due starts later than the job's
+// MaxLateness allows
+func (s *Scheduler) tooLate(job config.CronJob, due time.Time) bool {
+	limit, err := job.LatenessLimit()
+	if err != nil || limit == 0 {
+		return false
+	}
+	if lateness := time.Since(due); lateness > limit {
+		s.logger.Printf("[JOB_TOO_LATE] Skipping tick for job %s due at %s, %v late exceeds max_lateness %v", job.ID, due.Format(time.RFC3339), lateness.Round(time.Millisecond), limit)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+)
+
+// defaultMaxManualRuns caps test, invoke and rerun-secondary runs in
+// progress at once
+const defaultMaxManualRuns = 4
+
+// ErrManualLimit is returned by TestJob, Invoke and RerunSecondary when the
+// limit set with SetMaxManualRuns is reached
+var ErrManualLimit = errors.New("too many manual runs in progress")
+
+// SetMaxManualRuns caps how many runs started through the API (tests,
+// invocations and secondary reruns) may be in progress at once, so repeated
+// clicks or a script can't pile up goroutines; 0 removes the cap. Scheduled
+// ticks and reminders are not counted.
+func (s *Scheduler) SetMaxManualRuns(max int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.maxManualRuns = max
+}
+
+// acquireManualRun takes a manual run slot, returning the function that
+// gives it back, or ErrManualLimit when none is free
+func (s *Scheduler) acquireManualRun() (func(), error) {
+	s.mu.RLock()
+	max := s.maxManualRuns
+	s.mu.RUnlock()
+
+	if running := s.manualRuns.Add(1); max > 0 && running > int64(max) {
+		s.manualRuns.Add(-1)
+		s.logger.Printf("[MANUAL_RUN_LIMIT] Refusing a manual run, %d already in progress", max)
+		return nil, fmt.Errorf("%w: at most %d at a time", ErrManualLimit, max)
+	}
+	return func() { s.manualRuns.Add(-1) }, nil
+}
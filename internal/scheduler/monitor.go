@@ -0,0 +1,142 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+// monitorStates remembers which monitor jobs were down after their last
+// scheduled probe, persisted to a JSON file so neither a job update nor a
+// restart repeats a "went down" alert for an outage already reported
+type monitorStates struct {
+	mu   sync.Mutex
+	path string
+	down map[string]bool
+}
+
+func newMonitorStates() *monitorStates {
+	return &monitorStates{down: make(map[string]bool)}
+}
+
+// SetMonitorStateFile sets where the up or down state of monitor jobs is
+// persisted and loads any state already stored there
+func (s *Scheduler) SetMonitorStateFile(path string) error {
+	s.monitors.mu.Lock()
+	defer s.monitors.mu.Unlock()
+
+	s.monitors.path = path
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read monitor state file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &s.monitors.down); err != nil {
+		return fmt.Errorf("failed to parse monitor state file: %w", err)
+	}
+	return nil
+}
+
+// update records whether a monitor is down and returns whether it was down
+// before
+func (m *monitorStates) update(jobID string, down bool) (wasDown bool, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	wasDown = m.down[jobID]
+	if wasDown == down {
+		return wasDown, nil
+	}
+	if down {
+		m.down[jobID] = true
+	} else {
+		delete(m.down, jobID)
+	}
+	return wasDown, m.save()
+}
+
+// forget drops a deleted job's state
+func (m *monitorStates) forget(jobID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.down[jobID] {
+		return
+	}
+	delete(m.down, jobID)
+	m.save()
+}
+
+// save writes the state to the file. The caller must hold m.mu.
+func (m *monitorStates) save() error {
+	if m.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(m.down, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(m.path, data, 0644)
+}
+
+// executeMonitor runs a Monitor job's health probe. The primary is requested
+// and the run is recorded as a success or failure with the request's
+// latency as its primary timing. Only scheduled probes move the monitor
+// between up and down, and the job's OnPrimaryFailure only fires when one
+// finds the endpoint went down, not again while it stays down. Manual runs
+// report the probe without changing the state.
+func (s *Scheduler) executeMonitor(ctx context.Context, job config.CronJob, primary config.WebhookConfig, scope map[string]interface{}) {
+	logger := s.logFor(ctx)
+	run := runFrom(ctx)
+
+	start := time.Now()
+	_, err := s.executeWebhook(withStep(ctx, job.ID, "primary"), primary)
+	latency := time.Since(start)
+	run.timing(func(t *Timing) { t.PrimaryMs = milliseconds(latency) })
+	if err != nil {
+		run.fail(err)
+	}
+
+	if run.record.Trigger != TriggerSchedule {
+		if err != nil {
+			logger.Printf("[MONITOR_PROBE] Job %s is down after %v, state left as is for a %s run: %v", job.ID, latency, run.record.Trigger, err)
+		} else {
+			logger.Printf("[MONITOR_PROBE] Job %s answered in %v, state left as is for a %s run", job.ID, latency, run.record.Trigger)
+		}
+		return
+	}
+
+	wasDown, saveErr := s.monitors.update(job.ID, err != nil)
+	if saveErr != nil {
+		logger.Printf("[MONITOR_SAVE_ERROR] Failed to persist the state of monitor %s: %v", job.ID, saveErr)
+	}
+
+	switch {
+	case err != nil && wasDown:
+		logger.Printf("[MONITOR_DOWN] Job %s is still down after %v: %v", job.ID, latency, err)
+	case err != nil:
+		logger.Printf("[MONITOR_DOWN] Job %s went down after %v: %v", job.ID, latency, err)
+		if job.OnPrimaryFailure != nil {
+			s.executeFailureWebhook(ctx, job, err, scope)
+		}
+	case wasDown:
+		logger.Printf("[MONITOR_UP] Job %s is back up, answered in %v", job.ID, latency)
+	default:
+		logger.Printf("[MONITOR_UP] Job %s answered in %v", job.ID, latency)
+	}
+}
+
+// monitorMethod reports whether a monitor's primary may use method
+func monitorMethod(method string) bool {
+	return method == "" || containsFold([]string{http.MethodGet, http.MethodHead}, method)
+}
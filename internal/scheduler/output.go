@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/itchyny/gojq"
+)
+
+// Output returns a job's saved output with its detected content type. When
+// pretty is set, JSON outputs are re-indented for reading; the stored copy
+// that feeds the secondary webhook and jq extraction is never changed.
+func (s *Scheduler) Output(jobID string, pretty bool) (string, string, bool) {
+	s.mu.RLock()
+	output, ok := s.outputs[jobID]
+	s.mu.RUnlock()
+	if !ok {
+		return "", "", false
+	}
+
+	contentType := detectContentType(output)
+	if pretty {
+		output = prettyJSON(output)
+	}
+	return output, contentType, true
+}
+
+// prettyJSON re-indents a JSON document and returns anything else as-is.
+// Indenting works on the raw text, so large numbers keep their precision.
+func prettyJSON(body string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(strings.TrimSpace(body)), "", "  "); err != nil {
+		return body
+	}
+	return buf.String()
+}
+
+// transformOutput runs a primary webhook's OutputTransform over its JSON
+// response and returns the first result encoded as JSON, which is what
+// gets saved for the secondary. Numbers are decoded as json.Number so large
+// integers keep their precision.
+func transformOutput(ctx context.Context, expression, response string) (string, error) {
+	query, err := gojq.Parse(expression)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse output transform '%s': %w", expression, err)
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(response))
+	decoder.UseNumber()
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return "", fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	jqCtx, cancel := context.WithTimeout(ctx, jqTimeout)
+	defer cancel()
+
+	v, ok := query.RunWithContext(jqCtx, data).Next()
+	if !ok {
+		return "", fmt.Errorf("output transform '%s' produced no value", expression)
+	}
+	if err, isErr := v.(error); isErr {
+		return "", fmt.Errorf("failed to evaluate output transform '%s': %w", expression, err)
+	}
+
+	transformed, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode transformed output: %w", err)
+	}
+	return string(transformed), nil
+}
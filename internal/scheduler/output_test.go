@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"cron-microservice/internal/config"
+)
+
+func TestForEachSecondaryGetsTransformedOutput(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"orders": [{"id": "a", "blob": "x"}, {"id": "b", "blob": "y"}]}`))
+	}))
+	defer primary.Close()
+
+	var mu sync.Mutex
+	var bodies []string
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+	}))
+	defer secondary.Close()
+
+	job := config.CronJob{
+		ID:         "job",
+		Name:       "job",
+		Enabled:    true,
+		SaveOutput: true,
+		Primary: config.WebhookConfig{
+			URL:             primary.URL,
+			Method:          http.MethodGet,
+			OutputTransform: "{ids: [.orders[].id]}",
+			Enabled:         true,
+		},
+		Secondary: &config.WebhookConfig{
+			URL:          secondary.URL,
+			Method:       http.MethodPost,
+			ForEach:      ".ids",
+			BodyTemplate: `{"id": "{{item}}"}`,
+			Enabled:      true,
+		},
+	}
+	s, _ := newTestScheduler(t, job)
+
+	if r := s.executeJob(job, TriggerManual, nil); r.record.Status != StatusSuccess {
+		t.Fatalf("run status = %s (%s), want success", r.record.Status, r.record.Error)
+	}
+
+	sort.Strings(bodies)
+	if len(bodies) != 2 || bodies[0] != `{"id": "a"}` || bodies[1] != `{"id": "b"}` {
+		t.Errorf("secondary bodies = %q, want one per transformed id", bodies)
+	}
+}
+
+func TestHeadPrimaryWithSaveOutputLogsNoEmptyOutput(t *testing.T) {
+	server := echoServer(t, http.StatusOK)
+	job := config.CronJob{
+		ID:         "job",
+		Name:       "job",
+		Enabled:    true,
+		SaveOutput: true,
+		Primary:    config.WebhookConfig{URL: server.URL, Method: http.MethodHead, Enabled: true},
+	}
+	s, logs := newTestScheduler(t, job)
+
+	if r := s.executeJob(job, TriggerManual, nil); r.record.Status != StatusSuccess {
+		t.Fatalf("run status = %s (%s), want success", r.record.Status, r.record.Error)
+	}
+	if strings.Contains(logs.String(), "[OUTPUT_EMPTY]") {
+		t.Errorf("empty output logged for a HEAD primary:\n%s", logs.String())
+	}
+	if _, saved := s.outputs[job.ID]; saved {
+		t.Error("output saved for a HEAD primary")
+	}
+}
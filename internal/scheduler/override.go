@@ -0,0 +1,164 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"cron-microservice/internal/config"
+)
+
+// ScheduleOverride is a temporary schedule that replaces a job's persisted
+// one until it expires. Overrides live only in the scheduler and are never
+// written to the config.
+type ScheduleOverride struct {
+	JobID     string    `json:"job_id"`
+	Schedule  string    `json:"schedule"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+var (
+	// ErrInvalidOverride is returned by OverrideSchedule for a malformed
+	// schedule or a TTL that isn't positive
+	ErrInvalidOverride = errors.New("invalid schedule override")
+
+	// ErrOverrideNotEnabled is returned by OverrideSchedule for a job that
+	// is disabled, a draft or a template
+	ErrOverrideNotEnabled = errors.New("only enabled jobs can be overridden")
+
+	// ErrOverrideChained is returned by OverrideSchedule for a job that runs
+	// after another job instead of on a schedule of its own
+	ErrOverrideChained = errors.New("chained jobs can't be overridden")
+)
+
+type scheduleOverride struct {
+	ScheduleOverride
+	timer *time.Timer
+}
+
+// OverrideSchedule runs an enabled job on schedule instead of its own for
+// ttl, after which the persisted schedule is restored. Setting a new
+// override replaces the current one.
+func (s *Scheduler) OverrideSchedule(jobID, schedule string, ttl time.Duration) (ScheduleOverride, error) {
+	if ttl <= 0 {
+		return ScheduleOverride{}, fmt.Errorf("%w: ttl must be positive", ErrInvalidOverride)
+	}
+	if _, err := cron.ParseStandard(schedule); err != nil {
+		return ScheduleOverride{}, fmt.Errorf("%w: invalid schedule: %w", ErrInvalidOverride, err)
+	}
+
+	job, err := s.config.GetJob(jobID)
+	if err != nil {
+		return ScheduleOverride{}, err
+	}
+	if job.State() != config.StateEnabled {
+		return ScheduleOverride{}, fmt.Errorf("%w: job %s is %s", ErrOverrideNotEnabled, jobID, job.State())
+	}
+	if job.AfterJob != "" {
+		// It would run both on the override and after every parent firing
+		return ScheduleOverride{}, fmt.Errorf("%w: job %s runs after job %s", ErrOverrideChained, jobID, job.AfterJob)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entryID, err := s.addCronEntry(*job, schedule)
+	if err != nil {
+		return ScheduleOverride{}, fmt.Errorf("failed to add cron job: %w", err)
+	}
+
+	s.clearOverride(jobID)
+	if previous, exists := s.jobs[jobID]; exists {
+		s.cron.Remove(previous)
+	}
+	s.jobs[jobID] = entryID
+
+	override := &scheduleOverride{ScheduleOverride: ScheduleOverride{
+		JobID:     jobID,
+		Schedule:  schedule,
+		ExpiresAt: time.Now().Add(ttl),
+	}}
+	override.timer = time.AfterFunc(ttl, func() { s.expireOverride(override) })
+	s.overrides[jobID] = override
+
+	s.logger.Printf("[SCHEDULE_OVERRIDE] Job %s runs on %q until %s", jobID, schedule, override.ExpiresAt.Format(time.RFC3339))
+	return override.ScheduleOverride, nil
+}
+
+// ClearScheduleOverride ends a job's override early and restores its
+// persisted schedule. It reports whether an override was active.
+func (s *Scheduler) ClearScheduleOverride(jobID string) (bool, error) {
+	s.mu.RLock()
+	override, exists := s.overrides[jobID]
+	s.mu.RUnlock()
+	if !exists {
+		return false, nil
+	}
+
+	return true, s.restoreSchedule(override)
+}
+
+// Override returns a job's active schedule override, if any
+func (s *Scheduler) Override(jobID string) (ScheduleOverride, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	override, exists := s.overrides[jobID]
+	if !exists {
+		return ScheduleOverride{}, false
+	}
+	return override.ScheduleOverride, true
+}
+
+// activeOverrides lists the active overrides sorted by job ID. The caller
+// must hold s.mu.
+func (s *Scheduler) activeOverrides() []ScheduleOverride {
+	overrides := make([]ScheduleOverride, 0, len(s.overrides))
+	for _, override := range s.overrides {
+		overrides = append(overrides, override.ScheduleOverride)
+	}
+	sort.Slice(overrides, func(i, j int) bool { return overrides[i].JobID < overrides[j].JobID })
+	return overrides
+}
+
+// expireOverride restores the persisted schedule when an override's TTL ends
+func (s *Scheduler) expireOverride(override *scheduleOverride) {
+	if err := s.restoreSchedule(override); err != nil {
+		s.logger.Printf("[SCHEDULE_OVERRIDE_ERROR] Failed to restore schedule for job %s: %v", override.JobID, err)
+	}
+}
+
+// restoreSchedule re-adds the job from the config if override is still the
+// active one. addJob clears the override as part of rescheduling. The check
+// and the restore happen under one lock, so an override set in between is
+// never wiped.
+func (s *Scheduler) restoreSchedule(override *scheduleOverride) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.overrides[override.JobID] != override {
+		return nil
+	}
+
+	s.logger.Printf("[SCHEDULE_OVERRIDE_END] Restoring persisted schedule for job %s", override.JobID)
+
+	job, err := s.config.GetJob(override.JobID)
+	if err != nil {
+		// The job is gone; just drop the override and its cron entry
+		s.removeJob(override.JobID)
+		return nil
+	}
+	return s.addJob(*job)
+}
+
+// clearOverride stops and forgets a job's override. The caller must hold
+// s.mu and is responsible for the job's cron entry.
+func (s *Scheduler) clearOverride(jobID string) {
+	if override, exists := s.overrides[jobID]; exists {
+		override.timer.Stop()
+		delete(s.overrides, jobID)
+	}
+}
@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+func TestOverrideRejectsChainedJob(t *testing.T) {
+	parent := config.CronJob{ID: "parent", Name: "parent", Schedule: "@every 1h", Enabled: true}
+	child := config.CronJob{ID: "child", Name: "child", AfterJob: "parent", Enabled: true}
+	s, _ := newTestScheduler(t, parent, child)
+
+	if _, err := s.OverrideSchedule(child.ID, "* * * * *", time.Hour); !errors.Is(err, ErrOverrideChained) {
+		t.Errorf("OverrideSchedule on a chained job: err = %v, want ErrOverrideChained", err)
+	}
+	if _, active := s.Override(child.ID); active {
+		t.Error("a chained job got an override")
+	}
+}
+
+func TestStaleOverrideRestoreKeepsNewOverride(t *testing.T) {
+	job := config.CronJob{ID: "job", Name: "job", Schedule: "@every 1h", Enabled: true}
+	s, _ := newTestScheduler(t, job)
+	if err := s.AddJob(job); err != nil {
+		t.Fatalf("AddJob: %v", err)
+	}
+
+	if _, err := s.OverrideSchedule(job.ID, "* * * * *", time.Hour); err != nil {
+		t.Fatalf("OverrideSchedule: %v", err)
+	}
+	s.mu.RLock()
+	stale := s.overrides[job.ID]
+	s.mu.RUnlock()
+
+	if _, err := s.OverrideSchedule(job.ID, "*/5 * * * *", time.Hour); err != nil {
+		t.Fatalf("OverrideSchedule: %v", err)
+	}
+	if err := s.restoreSchedule(stale); err != nil {
+		t.Fatalf("restoreSchedule: %v", err)
+	}
+
+	override, active := s.Override(job.ID)
+	if !active || override.Schedule != "*/5 * * * *" {
+		t.Errorf("override = %+v (active %v), want the newer */5 override", override, active)
+	}
+}
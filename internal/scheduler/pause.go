@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+// Status summarizes the scheduler's runtime state
+type Status struct {
+	Paused            bool                              `json:"paused"`
+	ScheduledJobs     int                               `json:"scheduled_jobs"`
+	ActiveReminders   int                               `json:"active_reminders"`
+	HeldReminders     int                               `json:"held_reminders"`
+	SkippedTicks      int64                             `json:"skipped_ticks"`
+	RetryBudget       RetryBudgetStatus                 `json:"retry_budget"`
+	Overrides         []ScheduleOverride                `json:"schedule_overrides"`
+	Suspensions       []Suspension                      `json:"suspensions"`
+	PreviousVariables map[string]map[string]interface{} `json:"previous_variables"`
+}
+
+// PauseAll stops every scheduled job and reminder from firing until
+// ResumeAll is called. Entries stay registered; job ticks that arrive while
+// paused are skipped rather than queued, and reminders that come due are
+// held until ResumeAll.
+func (s *Scheduler) PauseAll() {
+	if s.paused.CompareAndSwap(false, true) {
+		s.logger.Printf("[SCHEDULER_PAUSED] All executions paused")
+	}
+}
+
+// ResumeAll lets scheduled jobs and reminders fire again, firing the
+// reminders that came due while paused right away
+func (s *Scheduler) ResumeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.paused.CompareAndSwap(true, false) {
+		return
+	}
+	s.logger.Printf("[SCHEDULER_RESUMED] Executions resumed")
+
+	for key, held := range s.heldReminders {
+		// A reminder removed or rescheduled while paused no longer fires
+		if s.reminders[key] == held.timer {
+			s.reminders[key] = time.AfterFunc(0, held.action)
+			s.logger.Printf("[REMINDER_RELEASED] Firing reminder %s for job %s, which came due while paused", held.reminderID, held.jobID)
+		}
+	}
+	clear(s.heldReminders)
+}
+
+// IsPaused reports whether executions are currently paused
+func (s *Scheduler) IsPaused() bool {
+	return s.paused.Load()
+}
+
+// Status returns the paused state along with job and reminder counts, the
+// retry budget, active schedule overrides, suspended jobs and the variables
+// stored from each job's last successful run, sensitive ones masked
+func (s *Scheduler) Status() Status {
+	sensitive := s.sensitiveNames()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return Status{
+		Paused:            s.paused.Load(),
+		ScheduledJobs:     len(s.jobs),
+		ActiveReminders:   len(s.reminders),
+		HeldReminders:     len(s.heldReminders),
+		SkippedTicks:      s.skippedTicks.Load(),
+		RetryBudget:       s.retryBudget.status(),
+		Overrides:         s.activeOverrides(),
+		Suspensions:       s.activeSuspensions(),
+		PreviousVariables: s.previousVariables.snapshot(sensitive),
+	}
+}
+
+// skipIfPaused logs and counts a skipped tick when the scheduler is paused
+func (s *Scheduler) skipIfPaused(kind, id string) bool {
+	if !s.paused.Load() {
+		return false
+	}
+
+	s.skippedTicks.Add(1)
+	s.logger.Printf("[SCHEDULER_PAUSED_SKIP] Skipping %s %s while paused", kind, id)
+	return true
+}
+
+// heldReminder is a reminder that came due while paused, along with the
+// timer that fired it
+type heldReminder struct {
+	jobID      string
+	reminderID string
+	timer      *time.Timer
+	action     func()
+}
+
+// holdIfPaused keeps a due reminder for ResumeAll to fire when the scheduler
+// is paused. It reports whether the reminder was held; a reminder whose
+// timer was removed meanwhile is dropped instead.
+func (s *Scheduler) holdIfPaused(job config.CronJob, reminder config.Reminder, action func()) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.paused.Load() {
+		return false
+	}
+
+	key := job.ID + "_" + reminder.ID
+	timer, armed := s.reminders[key]
+	if !armed {
+		return true
+	}
+	s.heldReminders[key] = heldReminder{jobID: job.ID, reminderID: reminder.ID, timer: timer, action: action}
+
+	s.logger.Printf("[REMINDER_HELD] Reminder %s for job %s came due while paused, it fires on resume", reminder.ID, job.ID)
+	return true
+}
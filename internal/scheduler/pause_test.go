@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+// waitFor polls cond until it holds or a second passes
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestReminderDueWhilePausedFiresOnResume(t *testing.T) {
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	job, reminder := reminderJob(server.URL)
+	reminder.Datetime = time.Now().Add(20 * time.Millisecond)
+	job.Reminders = []config.Reminder{reminder}
+	s, logs := newTestScheduler(t, job)
+
+	s.PauseAll()
+	s.mu.Lock()
+	err := s.scheduleReminder(job, reminder)
+	s.mu.Unlock()
+	if err != nil {
+		t.Fatalf("scheduleReminder: %v", err)
+	}
+
+	waitFor(t, "the reminder to be held", func() bool { return s.Status().HeldReminders == 1 })
+	if calls.Load() != 0 {
+		t.Fatal("the reminder fired while paused")
+	}
+	if !strings.Contains(logs.String(), "[REMINDER_HELD]") {
+		t.Errorf("held reminder not logged:\n%s", logs.String())
+	}
+
+	s.ResumeAll()
+	waitFor(t, "the reminder to fire", func() bool { return len(s.History(job.ID)) > 0 })
+	if got := calls.Load(); got != 1 {
+		t.Errorf("webhook called %d times after resume, want 1", got)
+	}
+	if s.Status().HeldReminders != 0 {
+		t.Error("the reminder is still held after resume")
+	}
+}
+
+func TestReminderRemovedWhilePausedDoesNotFire(t *testing.T) {
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	job, reminder := reminderJob(server.URL)
+	reminder.Datetime = time.Now().Add(20 * time.Millisecond)
+	job.Reminders = []config.Reminder{reminder}
+	s, _ := newTestScheduler(t, job)
+
+	s.PauseAll()
+	s.mu.Lock()
+	err := s.scheduleReminder(job, reminder)
+	s.mu.Unlock()
+	if err != nil {
+		t.Fatalf("scheduleReminder: %v", err)
+	}
+	waitFor(t, "the reminder to be held", func() bool { return s.Status().HeldReminders == 1 })
+
+	s.removeReminder(job.ID, reminder.ID)
+	s.ResumeAll()
+	time.Sleep(50 * time.Millisecond)
+	if got := calls.Load(); got != 0 {
+		t.Errorf("webhook called %d times for a reminder removed while paused", got)
+	}
+}
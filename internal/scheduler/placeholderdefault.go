@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// splitPlaceholder separates the variable name of a placeholder from the
+// default after ":-", as in {{token:-anonymous}}, reporting whether there
+// is one
+func splitPlaceholder(placeholder string) (name, fallback string, hasDefault bool) {
+	name, fallback, hasDefault = strings.Cut(placeholder, ":-")
+	return strings.TrimSpace(name), fallback, hasDefault
+}
+
+// resolvePlaceholder returns the variable a placeholder refers to, or its
+// default text when it has one and the variable is missing or empty
+func resolvePlaceholder(placeholder string, variables map[string]interface{}) (value interface{}, isDefault bool) {
+	name, fallback, hasDefault := splitPlaceholder(placeholder)
+	value = variables[name]
+	if hasDefault && isEmptyValue(value) {
+		return fallback, true
+	}
+	return value, false
+}
+
+// typedDefault returns a default used as a whole JSON value: defaults that
+// are valid JSON, like 0, true or [], keep their type and anything else is
+// a string
+func typedDefault(fallback string) interface{} {
+	decoder := json.NewDecoder(strings.NewReader(fallback))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return fallback
+	}
+	return value
+}
+
+// templateText formats a variable for a string template the way
+// processTemplate does: strings are escaped for use inside a JSON string
+// and other values are written as JSON
+func templateText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return templateEscaper.Replace(v)
+	case json.Number:
+		return v.String()
+	}
+	return stringifyValue(value)
+}
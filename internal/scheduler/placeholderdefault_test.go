@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestProcessTemplateDefaults(t *testing.T) {
+	s, _ := newTestScheduler(t)
+	for _, tc := range []struct {
+		name      string
+		template  string
+		variables map[string]interface{}
+		want      string
+	}{
+		{"present", `{"user": "{{token:-anonymous}}"}`, map[string]interface{}{"token": "abc"}, `{"user": "abc"}`},
+		{"present escaped", `{"user": "{{token:-anonymous}}"}`, map[string]interface{}{"token": `say "hi"`}, `{"user": "say \"hi\""}`},
+		{"missing with default", `{"user": "{{token:-anonymous}}"}`, nil, `{"user": "anonymous"}`},
+		{"empty with default", `{"user": "{{token:-anonymous}}"}`, map[string]interface{}{"token": ""}, `{"user": "anonymous"}`},
+		{"default escaped", `{"msg": "{{msg:-say "hi"}}"}`, nil, `{"msg": "say \"hi\""}`},
+		{"numeric default", `{"count": {{count:-0}}}`, nil, `{"count": 0}`},
+		{"missing without default", `{"user": "{{token}}"}`, nil, `{"user": "{{token}}"}`},
+	} {
+		got, err := s.processTemplate(context.Background(), tc.template, tc.variables)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s: got %s, want %s", tc.name, got, tc.want)
+		}
+		if tc.name != "missing without default" && !json.Valid([]byte(got)) {
+			t.Errorf("%s: %s is not valid JSON", tc.name, got)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"cron-microservice/internal/config"
+)
+
+// previousVariables remembers the variables each job's jq selectors
+// extracted on its last successful run, persisted to a JSON file so a
+// cursor survives restarts. Values of a job's sensitive variables are only
+// kept in memory.
+type previousVariables struct {
+	mu        sync.Mutex
+	path      string
+	values    map[string]map[string]interface{}
+	sensitive map[string][]string // Each job's sensitive_variables, left out of the file
+}
+
+func newPreviousVariables() *previousVariables {
+	return &previousVariables{
+		values:    make(map[string]map[string]interface{}),
+		sensitive: make(map[string][]string),
+	}
+}
+
+// SetPreviousVariablesFile sets where variables from each job's last run are
+// persisted and loads any values already stored there
+func (s *Scheduler) SetPreviousVariablesFile(path string) error {
+	s.previousVariables.mu.Lock()
+	defer s.previousVariables.mu.Unlock()
+
+	s.previousVariables.path = path
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read previous variables file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &s.previousVariables.values); err != nil {
+		return fmt.Errorf("failed to parse previous variables file: %w", err)
+	}
+	return nil
+}
+
+// selectorNames lists the variables a job's precheck and secondary jq
+// selectors extract, sorted
+func selectorNames(job config.CronJob) []string {
+	var names []string
+	for _, webhook := range []*config.WebhookConfig{job.Precheck, job.Secondary} {
+		if webhook == nil {
+			continue
+		}
+		for name := range webhook.JQSelectors {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// seed puts the values a job's selectors extracted on its last successful
+// run in scope as {{prev.<name>}}. Before any such run they are empty.
+func (p *previousVariables) seed(job config.CronJob, scope map[string]interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored := p.values[job.ID]
+	for _, name := range selectorNames(job) {
+		value, ok := stored[name]
+		if !ok {
+			value = ""
+		}
+		scope["prev."+name] = value
+	}
+}
+
+// recordPreviousVariables stores the selector variables in scope once a run
+// has succeeded, so the next run sees them as {{prev.<name>}}. Variables the
+// run did not extract keep their previous value.
+func (s *Scheduler) recordPreviousVariables(ctx context.Context, job config.CronJob, scope map[string]interface{}) {
+	run := runFrom(ctx)
+	run.mu.Lock()
+	status := run.record.Status
+	run.mu.Unlock()
+	if status != StatusSuccess {
+		return
+	}
+
+	names := selectorNames(job)
+	if len(names) == 0 {
+		return
+	}
+
+	p := s.previousVariables
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored := p.values[job.ID]
+	if stored == nil {
+		stored = make(map[string]interface{}, len(names))
+	}
+	changed := false
+	for _, name := range names {
+		if value, ok := scope[name]; ok {
+			stored[name] = value
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	p.values[job.ID] = stored
+	p.sensitive[job.ID] = job.SensitiveVariables
+	if err := p.save(); err != nil {
+		s.logFor(ctx).Printf("[PREV_VARIABLES_SAVE_ERROR] Failed to persist previous variables for job %s: %v", job.ID, err)
+	}
+}
+
+// forget drops a deleted job's state
+func (p *previousVariables) forget(jobID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, known := p.values[jobID]; !known {
+		return
+	}
+	delete(p.values, jobID)
+	delete(p.sensitive, jobID)
+	p.save()
+}
+
+// snapshot copies the stored values for the scheduler status, masking the
+// values of each job's sensitive variables
+func (p *previousVariables) snapshot(sensitive map[string][]string) map[string]map[string]interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	values := make(map[string]map[string]interface{}, len(p.values))
+	for jobID, stored := range p.values {
+		copied := make(map[string]interface{}, len(stored))
+		for name, value := range stored {
+			if isSensitive(sensitive[jobID], name) {
+				value = redactedValue
+			}
+			copied[name] = value
+		}
+		values[jobID] = copied
+	}
+	return values
+}
+
+// save writes the values to the file, leaving out sensitive variables. The
+// caller must hold p.mu.
+func (p *previousVariables) save() error {
+	if p.path == "" {
+		return nil
+	}
+
+	persisted := make(map[string]map[string]interface{}, len(p.values))
+	for jobID, stored := range p.values {
+		kept := make(map[string]interface{}, len(stored))
+		for name, value := range stored {
+			if !isSensitive(p.sensitive[jobID], name) {
+				kept[name] = value
+			}
+		}
+		persisted[jobID] = kept
+	}
+
+	data, err := json.MarshalIndent(persisted, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p.path, data, 0644)
+}
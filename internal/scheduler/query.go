@@ -0,0 +1,113 @@
+package scheduler
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+
+	"cron-microservice/internal/config"
+)
+
+// renderRequest renders the per-call parts of a webhook from scope: its
+// headers and its query parameters
+func (s *Scheduler) renderRequest(ctx context.Context, webhook config.WebhookConfig, scope map[string]interface{}) config.WebhookConfig {
+	s.computeVariables(ctx, webhook, scope)
+	runFrom(ctx).redact(scope)
+	return s.renderQuery(ctx, s.renderHeaders(ctx, webhook, scope), scope)
+}
+
+// renderQuery appends a webhook's QueryParams to its URL, and to each of its
+// round-robin URLs. A value that is exactly one placeholder for an array,
+// such as "{{ids}}", becomes one parameter per element (?id=1&id=2), and an
+// empty array, missing or null variable leaves the parameter out. Other
+// values are rendered as text. Every value is URL-encoded.
+func (s *Scheduler) renderQuery(ctx context.Context, webhook config.WebhookConfig, scope map[string]interface{}) config.WebhookConfig {
+	if len(webhook.QueryParams) == 0 {
+		return webhook
+	}
+
+	query := queryValues(webhook.QueryParams, scope)
+	if len(query) == 0 {
+		s.logFor(ctx).Printf("[WEBHOOK_QUERY_EMPTY] Every query parameter rendered empty, none appended")
+		return webhook
+	}
+
+	encoded := encodeQuery(webhook.QueryParams, query)
+	webhook.URL = appendQuery(webhook.URL, encoded)
+	if len(webhook.URLs) > 0 {
+		urls := make([]string, len(webhook.URLs))
+		for i, u := range webhook.URLs {
+			urls[i] = appendQuery(u, encoded)
+		}
+		webhook.URLs = urls
+	}
+	return webhook
+}
+
+// queryValues renders each query parameter template into its values
+func queryValues(params map[string]string, scope map[string]interface{}) map[string][]string {
+	query := make(map[string][]string, len(params))
+	for key, value := range params {
+		// A lone placeholder keeps the variable's structure
+		if match := placeholderPattern.FindStringSubmatch(value); match != nil && match[0] == strings.TrimSpace(value) {
+			value, _ := resolvePlaceholder(match[1], scope)
+			switch v := value.(type) {
+			case nil:
+			case []interface{}:
+				for _, element := range v {
+					query[key] = append(query[key], stringifyValue(element))
+				}
+			default:
+				query[key] = []string{stringifyValue(v)}
+			}
+			continue
+		}
+
+		query[key] = []string{placeholderPattern.ReplaceAllStringFunc(value, func(placeholder string) string {
+			value, _ := resolvePlaceholder(placeholderPattern.FindStringSubmatch(placeholder)[1], scope)
+			return stringifyValue(value)
+		})}
+	}
+	return query
+}
+
+// encodeQuery URL-encodes query values as key=value pairs, with keys sorted
+// and repeated keys in element order
+func encodeQuery(params map[string]string, query map[string][]string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			pairs = append(pairs, url.QueryEscape(key)+"="+url.QueryEscape(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// appendQuery adds an encoded query to a URL, after any query it already
+// has and before its fragment
+func appendQuery(rawURL, encoded string) string {
+	if encoded == "" {
+		return rawURL
+	}
+
+	base, fragment, hasFragment := strings.Cut(rawURL, "#")
+	switch {
+	case !strings.Contains(base, "?"):
+		base += "?" + encoded
+	case strings.HasSuffix(base, "?") || strings.HasSuffix(base, "&"):
+		base += encoded
+	default:
+		base += "&" + encoded
+	}
+	if hasFragment {
+		base += "#" + fragment
+	}
+	return base
+}
@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"cron-microservice/internal/config"
+)
+
+func TestQueryValuesExpandArrays(t *testing.T) {
+	scope := map[string]interface{}{
+		"ids":   []interface{}{1, "two", 3.5},
+		"none":  []interface{}{},
+		"null":  nil,
+		"name":  "a b&c",
+		"flags": []interface{}{true, false},
+	}
+	for _, tc := range []struct {
+		name   string
+		params map[string]string
+		want   string
+	}{
+		{"array", map[string]string{"id": "{{ids}}"}, "id=1&id=two&id=3.5"},
+		{"spaced placeholder", map[string]string{"id": " {{ ids }} "}, "id=1&id=two&id=3.5"},
+		{"empty array", map[string]string{"id": "{{none}}", "q": "x"}, "q=x"},
+		{"null", map[string]string{"id": "{{null}}", "q": "x"}, "q=x"},
+		{"missing", map[string]string{"id": "{{missing}}", "q": "x"}, "q=x"},
+		{"escaped", map[string]string{"name": "{{name}}"}, "name=a+b%26c"},
+		{"booleans", map[string]string{"flag": "{{flags}}"}, "flag=true&flag=false"},
+		{"array in text", map[string]string{"id": "ids:{{ids}}"}, "id=ids%3A%5B1%2C%22two%22%2C3.5%5D"},
+		{"sorted keys", map[string]string{"b": "2", "a": "{{ids}}"}, "a=1&a=two&a=3.5&b=2"},
+	} {
+		got := encodeQuery(tc.params, queryValues(tc.params, scope))
+		if got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRenderQueryAppendsToEveryURL(t *testing.T) {
+	s, logs := newTestScheduler(t)
+	webhook := config.WebhookConfig{
+		URL:         "http://example.com/a?x=1#top",
+		URLs:        []string{"http://one.example.com/", "http://two.example.com/?"},
+		QueryParams: map[string]string{"id": "{{ids}}"},
+	}
+
+	rendered := s.renderQuery(context.Background(), webhook, map[string]interface{}{"ids": []interface{}{1, 2}})
+	if want := "http://example.com/a?x=1&id=1&id=2#top"; rendered.URL != want {
+		t.Errorf("URL %q, want %q", rendered.URL, want)
+	}
+	for i, want := range []string{"http://one.example.com/?id=1&id=2", "http://two.example.com/?id=1&id=2"} {
+		if rendered.URLs[i] != want {
+			t.Errorf("URLs[%d] %q, want %q", i, rendered.URLs[i], want)
+		}
+	}
+
+	rendered = s.renderQuery(context.Background(), webhook, map[string]interface{}{"ids": []interface{}{}})
+	if rendered.URL != webhook.URL {
+		t.Errorf("an empty array changed the URL to %q", rendered.URL)
+	}
+	if !strings.Contains(logs.String(), "[WEBHOOK_QUERY_EMPTY]") {
+		t.Errorf("no [WEBHOOK_QUERY_EMPTY] log for an empty query: %s", logs.String())
+	}
+}
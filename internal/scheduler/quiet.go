@@ -0,0 +1,33 @@
+package scheduler
+
+import (
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+// deferUntilQuietHoursEnd re-arms a due reminder to fire when its job's
+// quiet hours end, if the job defers reminders and is in quiet hours. It
+// reports whether the reminder was deferred; a reminder whose timer was
+// removed meanwhile is dropped instead.
+func (s *Scheduler) deferUntilQuietHoursEnd(job config.CronJob, reminder config.Reminder, action func()) bool {
+	if job.QuietHours == nil || !job.QuietHours.DeferReminders {
+		return false
+	}
+	until, quiet := job.QuietHours.Until(time.Now())
+	if !quiet {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := job.ID + "_" + reminder.ID
+	if _, armed := s.reminders[key]; !armed {
+		return true
+	}
+	s.reminders[key] = time.AfterFunc(time.Until(until), action)
+
+	s.logger.Printf("[REMINDER_QUIET_DEFERRED] Reminder %s for job %s deferred to the end of quiet hours at %s", reminder.ID, job.ID, until.Format(time.RFC3339))
+	return true
+}
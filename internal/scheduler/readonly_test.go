@@ -0,0 +1,98 @@
+package scheduler
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+// assertNoSaveErrors fails when a background change tried to save a
+// read-only config
+func assertNoSaveErrors(t *testing.T, logs *syncBuffer) {
+	t.Helper()
+	for _, line := range strings.Split(logs.String(), "\n") {
+		if strings.Contains(line, "ERROR]") {
+			t.Errorf("unexpected error logged: %s", line)
+		}
+	}
+}
+
+func TestReadOnlyAutoDisableSkipped(t *testing.T) {
+	server := echoServer(t, http.StatusInternalServerError)
+	job := config.CronJob{
+		ID:                     "job",
+		Name:                   "job",
+		Enabled:                true,
+		MaxConsecutiveFailures: 1,
+		Primary:                config.WebhookConfig{URL: server.URL, Method: http.MethodPost, Enabled: true},
+	}
+	s, logs := newTestScheduler(t, job)
+	s.config.SetReadOnly(true)
+
+	s.executeJob(job, TriggerSchedule, nil)
+	s.executeJob(job, TriggerSchedule, nil)
+
+	stored, err := s.config.GetJob(job.ID)
+	if err != nil {
+		t.Fatalf("GetJob: %v", err)
+	}
+	if !stored.Enabled || stored.DisabledReason != "" {
+		t.Errorf("read-only job changed: enabled = %v, reason = %q", stored.Enabled, stored.DisabledReason)
+	}
+	if !strings.Contains(logs.String(), "[JOB_AUTO_DISABLE_SKIPPED]") {
+		t.Errorf("skipped auto-disable not logged:\n%s", logs.String())
+	}
+}
+
+func TestReadOnlyReminderLeavesConfigAlone(t *testing.T) {
+	for _, status := range []int{http.StatusOK, http.StatusInternalServerError} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			server := echoServer(t, status)
+			job, reminder := reminderJob(server.URL)
+			s, logs := newTestScheduler(t, job)
+			s.config.SetReadOnly(true)
+
+			s.executeReminder(job, reminder, TriggerReminder)
+
+			kept := storedReminder(t, s)
+			if kept == nil {
+				t.Fatal("reminder deleted from a read-only config")
+			}
+			if kept.LastStatus != "" || kept.LastRunAt != nil {
+				t.Errorf("run recorded on a read-only config: %+v", kept)
+			}
+			for _, tag := range []string{"[REMINDER_SAVE_ERROR]", "[REMINDER_CLEANUP_ERROR]"} {
+				if strings.Contains(logs.String(), tag) {
+					t.Errorf("spurious %s in logs:\n%s", tag, logs.String())
+				}
+			}
+		})
+	}
+}
+
+func TestReadOnlySuspensionEnds(t *testing.T) {
+	until := time.Now().Add(-time.Second)
+	job := config.CronJob{ID: "job", Name: "job", Enabled: true, Schedule: "@every 1h", SuspendedUntil: &until}
+	s, logs := newTestScheduler(t, job)
+	s.config.SetReadOnly(true)
+
+	s.endSuspension(job.ID, until)
+
+	stored, err := s.config.GetJob(job.ID)
+	if err != nil {
+		t.Fatalf("GetJob: %v", err)
+	}
+	if stored.SuspendedUntil == nil {
+		t.Error("suspended_until cleared in a read-only config")
+	}
+	if suspended(*stored) {
+		t.Error("job still suspended after its suspension ended")
+	}
+	assertNoSaveErrors(t, logs)
+	if !strings.Contains(logs.String(), "[JOB_SUSPEND_END]") {
+		t.Errorf("end of suspension not logged:\n%s", logs.String())
+	}
+}
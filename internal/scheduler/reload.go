@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"reflect"
+	"sort"
+)
+
+// reload reconciles the scheduled jobs with a snapshot of the configuration
+// in one step under s.mu: jobs missing from the snapshot are removed, new
+// jobs are added and changed jobs are rescheduled. Unchanged jobs keep
+// their cron entry, failure count, override and suspension.
+//
+// API handlers change the configuration before the scheduler, so an edit
+// racing with a reload either lands in the snapshot or reaches the
+// scheduler after the reload; a job deleted during a reload is never
+// scheduled again.
+func (s *Scheduler) reload() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := s.config.GetAllJobs()
+
+	inConfig := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		inConfig[job.ID] = true
+	}
+
+	removed := []string{}
+	for jobID := range s.loaded {
+		if !inConfig[jobID] {
+			removed = append(removed, jobID)
+		}
+	}
+	sort.Strings(removed)
+	for _, jobID := range removed {
+		s.removeJob(jobID)
+		s.logger.Printf("[JOB_RELOAD_REMOVED] Unscheduled job %s, which is no longer configured", jobID)
+	}
+
+	for _, job := range jobs {
+		previous, known := s.loaded[job.ID]
+		if known && reflect.DeepEqual(previous, job) {
+			continue
+		}
+		if job.Draft && !known {
+			s.logger.Printf("[JOB_DRAFT] Not scheduling draft job %s", job.ID)
+			continue
+		}
+		if err := s.addJob(job); err != nil {
+			s.logger.Printf("[JOB_LOAD_ERROR] Failed to load job %s: %v", job.ID, err)
+		}
+	}
+}
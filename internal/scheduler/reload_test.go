@@ -0,0 +1,143 @@
+package scheduler
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"cron-microservice/internal/config"
+)
+
+func reloadJob(id, schedule string) config.CronJob {
+	return config.CronJob{
+		ID:       id,
+		Name:     id,
+		Schedule: schedule,
+		Enabled:  true,
+		Primary:  config.WebhookConfig{URL: "http://example.com", Method: "GET", Enabled: true},
+	}
+}
+
+func TestReloadReconcilesJobs(t *testing.T) {
+	s, _ := newTestScheduler(t, reloadJob("kept", "@every 1h"), reloadJob("changed", "@every 1h"), reloadJob("removed", "@every 1h"))
+	if err := s.LoadJobs(); err != nil {
+		t.Fatalf("LoadJobs: %v", err)
+	}
+	keptEntry := s.jobs["kept"]
+
+	changed := reloadJob("changed", "@every 1h")
+	changed.Name = "renamed"
+	for _, job := range []config.CronJob{changed, reloadJob("added", "@every 1h")} {
+		if err := s.config.AddJob(job); err != nil {
+			t.Fatalf("AddJob(%s): %v", job.ID, err)
+		}
+	}
+	if err := s.config.DeleteJob("removed"); err != nil {
+		t.Fatalf("DeleteJob: %v", err)
+	}
+	s.reload()
+
+	if s.jobs["kept"] != keptEntry {
+		t.Errorf("unchanged job got a new cron entry: %d, was %d", s.jobs["kept"], keptEntry)
+	}
+	if got := s.loaded["changed"].Name; got != "renamed" {
+		t.Errorf("changed job loaded with name %q, want renamed", got)
+	}
+	if _, ok := s.jobs["added"]; !ok {
+		t.Error("added job was not scheduled")
+	}
+	if _, ok := s.jobs["removed"]; ok {
+		t.Error("removed job is still scheduled")
+	}
+	if _, ok := s.loaded["removed"]; ok {
+		t.Error("removed job is still loaded")
+	}
+}
+
+func TestReloadRetriesJobThatFailedToLoad(t *testing.T) {
+	s, _ := newTestScheduler(t, reloadJob("job", "not a schedule"))
+	if err := s.LoadJobs(); err != nil {
+		t.Fatalf("LoadJobs: %v", err)
+	}
+	if _, ok := s.loaded["job"]; ok {
+		t.Fatal("a job that failed to schedule was recorded as loaded")
+	}
+
+	// A broken edit of a loaded job unschedules it until it is fixed
+	if err := s.config.AddJob(reloadJob("job", "@every 1h")); err != nil {
+		t.Fatalf("AddJob: %v", err)
+	}
+	s.reload()
+	if _, ok := s.jobs["job"]; !ok {
+		t.Fatal("reload did not retry the job once its schedule was fixed")
+	}
+
+	if err := s.config.AddJob(reloadJob("job", "not a schedule")); err != nil {
+		t.Fatalf("AddJob: %v", err)
+	}
+	s.reload()
+	if _, ok := s.loaded["job"]; ok {
+		t.Error("a job whose update failed to schedule is still recorded as loaded")
+	}
+	if _, ok := s.jobs["job"]; ok {
+		t.Error("a job whose update failed to schedule still has a cron entry")
+	}
+}
+
+func TestReloadConcurrentWithEdits(t *testing.T) {
+	s, _ := newTestScheduler(t)
+	ids := []string{"a", "b", "c", "d"}
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 4; worker++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				s.reload()
+			}
+		}()
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				id := ids[(worker+i)%len(ids)]
+				if i%3 == 2 {
+					// Another worker may have deleted it already
+					s.config.DeleteJob(id)
+					continue
+				}
+				job := reloadJob(id, fmt.Sprintf("@every %dm", 1+i%5))
+				if err := s.config.AddJob(job); err != nil {
+					t.Errorf("config.AddJob(%s): %v", id, err)
+				}
+				if err := s.AddJob(job); err != nil {
+					t.Errorf("AddJob(%s): %v", id, err)
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+	s.reload()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	want := map[string]config.CronJob{}
+	for _, job := range s.config.GetAllJobs() {
+		want[job.ID] = job
+	}
+	if !reflect.DeepEqual(s.loaded, want) {
+		t.Errorf("loaded jobs %v, want the config's %v", s.loaded, want)
+	}
+	if len(s.jobs) != len(want) {
+		t.Errorf("%d cron entries for %d configured jobs", len(s.jobs), len(want))
+	}
+	for id := range want {
+		if _, ok := s.jobs[id]; !ok {
+			t.Errorf("job %s is configured but has no cron entry", id)
+		}
+	}
+	if entries := len(s.cron.Entries()); entries != len(s.jobs) {
+		t.Errorf("cron has %d entries, want %d: some were leaked", entries, len(s.jobs))
+	}
+}
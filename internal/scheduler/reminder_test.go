@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+// reminderJob returns a job with one reminder whose webhook is url
+func reminderJob(url string) (config.CronJob, config.Reminder) {
+	reminder := config.Reminder{ID: "r1", Text: "hello", Datetime: time.Now().Add(time.Hour)}
+	job := config.CronJob{
+		ID:        "job",
+		Name:      "job",
+		Primary:   config.WebhookConfig{URL: url, Method: http.MethodPost, Enabled: true},
+		Reminders: []config.Reminder{reminder},
+	}
+	return job, reminder
+}
+
+func TestReminderJobDeletedWhileRunning(t *testing.T) {
+	for _, status := range []int{http.StatusOK, http.StatusInternalServerError} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			var s *Scheduler
+			var job config.CronJob
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The job is deleted while its reminder's webhook is in flight
+				if err := s.config.DeleteJob(job.ID); err != nil {
+					t.Errorf("DeleteJob: %v", err)
+				}
+				s.RemoveJob(job.ID)
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			job, reminder := reminderJob(server.URL)
+			s, logs := newTestScheduler(t, job)
+
+			if r := s.executeReminder(job, reminder, TriggerReminder); r == nil {
+				t.Fatal("executeReminder returned no run")
+			}
+			output := logs.String()
+			for _, tag := range []string{"[REMINDER_CLEANUP_ERROR]", "[REMINDER_SAVE_ERROR]"} {
+				if strings.Contains(output, tag) {
+					t.Errorf("spurious %s in logs:\n%s", tag, output)
+				}
+			}
+			if !strings.Contains(output, "[REMINDER_JOB_GONE]") {
+				t.Errorf("deleted job not logged:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestReminderJobDeletedBeforeFiring(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	job, reminder := reminderJob(server.URL)
+	s, logs := newTestScheduler(t)
+
+	if r := s.executeReminder(job, reminder, TriggerReminder); r != nil {
+		t.Errorf("executeReminder ran a reminder of a deleted job: %+v", r.record)
+	}
+	if requests != 0 {
+		t.Errorf("webhook called %d times for a deleted job", requests)
+	}
+	if strings.Contains(logs.String(), "ERROR]") {
+		t.Errorf("spurious error in logs:\n%s", logs.String())
+	}
+}
+
+func TestRemindersArmedRegardlessOfJobState(t *testing.T) {
+	disabled := false
+	for _, tt := range []struct {
+		name    string
+		setup   func(*config.CronJob)
+		armed   bool
+		hasCron bool
+	}{
+		{"enabled job", func(job *config.CronJob) { job.Enabled = true }, true, true},
+		{"disabled job", func(job *config.CronJob) { job.Enabled = false }, true, false},
+		{"disabled cascading job", func(job *config.CronJob) { job.ReminderPolicy = ReminderPolicyCascade }, true, false},
+		{"disabled reminder", func(job *config.CronJob) { job.Enabled = true; job.Reminders[0].Enabled = &disabled }, false, true},
+		{"draft", func(job *config.CronJob) { job.Enabled = true; job.Draft = true }, false, false},
+		{"template", func(job *config.CronJob) { job.Enabled = true; job.Template = true }, false, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			job, reminder := reminderJob("http://example.com")
+			job.Schedule = "@every 1h"
+			tt.setup(&job)
+			s, _ := newTestScheduler(t, job)
+			if err := s.AddJob(job); err != nil {
+				t.Fatalf("AddJob: %v", err)
+			}
+
+			s.mu.RLock()
+			defer s.mu.RUnlock()
+			if _, armed := s.reminders[job.ID+"_"+reminder.ID]; armed != tt.armed {
+				t.Errorf("reminder armed = %v, want %v", armed, tt.armed)
+			}
+			if _, hasCron := s.jobs[job.ID]; hasCron != tt.hasCron {
+				t.Errorf("cron entry = %v, want %v", hasCron, tt.hasCron)
+			}
+		})
+	}
+}
+
+func TestDisablingJobKeepsReminderArmed(t *testing.T) {
+	job, reminder := reminderJob("http://example.com")
+	job.Schedule = "@every 1h"
+	job.Enabled = true
+	s, _ := newTestScheduler(t, job)
+	if err := s.AddJob(job); err != nil {
+		t.Fatalf("AddJob: %v", err)
+	}
+
+	job.Enabled = false
+	if err := s.AddJob(job); err != nil {
+		t.Fatalf("AddJob: %v", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, armed := s.reminders[job.ID+"_"+reminder.ID]; !armed {
+		t.Error("disabling the job dropped its reminder")
+	}
+	if _, hasCron := s.jobs[job.ID]; hasCron {
+		t.Error("the disabled job kept its cron entry")
+	}
+}
@@ -0,0 +1,30 @@
+package scheduler
+
+import (
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+// Reminder policies for CronJob.ReminderPolicy, deciding whether a job's
+// reminders follow its enabled state and suspension
+const (
+	ReminderPolicyIndependent = "independent" // Reminders fire whether or not the job is enabled or suspended (default)
+	ReminderPolicyCascade     = "cascade"     // Reminders due while the job is disabled or suspended are dropped
+)
+
+// reminderSuppressed reports why a job's reminder policy holds back its
+// reminders right now, or "" when they may fire. Pausing the whole
+// scheduler holds reminders under either policy.
+func reminderSuppressed(job config.CronJob) string {
+	if job.ReminderPolicy != ReminderPolicyCascade {
+		return ""
+	}
+	if !job.Enabled {
+		return "the job is disabled"
+	}
+	if suspended(job) {
+		return "the job is suspended until " + job.SuspendedUntil.Format(time.RFC3339)
+	}
+	return ""
+}
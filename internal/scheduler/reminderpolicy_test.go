@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+func TestReminderSuppressed(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	tests := []struct {
+		name       string
+		policy     string
+		enabled    bool
+		suspended  *time.Time
+		suppressed bool
+	}{
+		{"default, enabled", "", true, nil, false},
+		{"default, disabled", "", false, nil, false},
+		{"default, suspended", "", true, &future, false},
+		{"independent, disabled", ReminderPolicyIndependent, false, nil, false},
+		{"independent, suspended", ReminderPolicyIndependent, true, &future, false},
+		{"cascade, enabled", ReminderPolicyCascade, true, nil, false},
+		{"cascade, disabled", ReminderPolicyCascade, false, nil, true},
+		{"cascade, suspended", ReminderPolicyCascade, true, &future, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := config.CronJob{ID: "job", ReminderPolicy: tt.policy, Enabled: tt.enabled, SuspendedUntil: tt.suspended}
+			if got := reminderSuppressed(job) != ""; got != tt.suppressed {
+				t.Errorf("suppressed = %v, want %v", got, tt.suppressed)
+			}
+		})
+	}
+}
+
+func TestReminderPolicyOnDisabledJob(t *testing.T) {
+	for _, tt := range []struct {
+		policy string
+		fires  bool
+	}{
+		{ReminderPolicyIndependent, true},
+		{ReminderPolicyCascade, false},
+	} {
+		t.Run(tt.policy, func(t *testing.T) {
+			var calls atomic.Int64
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+			}))
+			defer server.Close()
+
+			job, reminder := reminderJob(server.URL)
+			job.ReminderPolicy = tt.policy
+			reminder.Datetime = time.Now().Add(20 * time.Millisecond)
+			job.Reminders = []config.Reminder{reminder}
+			s, logs := newTestScheduler(t, job)
+
+			s.mu.Lock()
+			err := s.scheduleReminder(job, reminder)
+			s.mu.Unlock()
+			if err != nil {
+				t.Fatalf("scheduleReminder: %v", err)
+			}
+
+			// A reminder that fires is recorded in the history; a dropped
+			// one only loses its timer
+			deadline := time.Now().Add(2 * time.Second)
+			for {
+				s.mu.RLock()
+				armed := len(s.reminders)
+				s.mu.RUnlock()
+				if tt.fires && len(s.History(job.ID)) > 0 || !tt.fires && armed == 0 {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatal("reminder timer never fired")
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			if fired := calls.Load() > 0; fired != tt.fires {
+				t.Errorf("fired = %v, want %v\n%s", fired, tt.fires, logs.String())
+			}
+			if suppressed := strings.Contains(logs.String(), "[REMINDER_SUPPRESSED]"); suppressed == tt.fires {
+				t.Errorf("suppression logged = %v, want %v", suppressed, !tt.fires)
+			}
+		})
+	}
+}
+
+func TestCheckJobRejectsUnknownReminderPolicy(t *testing.T) {
+	s, _ := newTestScheduler(t)
+	for policy, valid := range map[string]bool{
+		"":                        true,
+		ReminderPolicyIndependent: true,
+		ReminderPolicyCascade:     true,
+		"cascde":                  false,
+	} {
+		job := config.CronJob{ID: "job", Schedule: "* * * * *", ReminderPolicy: policy}
+		err := CheckJob(s.config, &job)
+		if (err == nil) != valid {
+			t.Errorf("policy %q: err = %v, want valid %v", policy, err, valid)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cron-microservice/internal/config"
+)
+
+var (
+	// ErrReminderNotFound is returned by RetryReminder when the job has no
+	// reminder with the given ID
+	ErrReminderNotFound = errors.New("reminder not found")
+
+	// ErrReminderNotFailed is returned by RetryReminder for a reminder whose
+	// last run did not fail
+	ErrReminderNotFailed = errors.New("reminder has not failed")
+
+	// ErrReminderRetrying is returned by RetryReminder while another retry
+	// of the same reminder is running
+	ErrReminderRetrying = errors.New("reminder retry already in progress")
+)
+
+// maxReminderResponseSize caps the primary response kept for a reminder
+// whose secondary failed
+const maxReminderResponseSize = 1 << 20
+
+// keepReminderResponse remembers the primary response of a reminder whose
+// secondary failed, so a retry sends only the secondary. Responses are kept
+// in memory only, never in the config, as they may carry secrets; after a
+// restart, or when the response is over maxReminderResponseSize, a retry
+// sends the primary again. It reports whether the response was kept.
+func (s *Scheduler) keepReminderResponse(jobID, reminderID, response string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := jobID + "_" + reminderID
+	if len(response) > maxReminderResponseSize {
+		delete(s.reminderResponses, key)
+		return false
+	}
+	s.reminderResponses[key] = response
+	return true
+}
+
+// reminderResponse returns the primary response kept for a reminder's retry
+func (s *Scheduler) reminderResponse(jobID, reminderID string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	response, ok := s.reminderResponses[jobID+"_"+reminderID]
+	return response, ok
+}
+
+// forgetReminderResponse drops the primary response kept for a reminder
+func (s *Scheduler) forgetReminderResponse(jobID, reminderID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.reminderResponses, jobID+"_"+reminderID)
+}
+
+// keepFailedReminder records a failed run on the reminder in the config
+// instead of deleting it, so the failure stays visible and can be retried
+func (s *Scheduler) keepFailedReminder(ctx context.Context, jobID, reminderID string, failure config.ReminderRun) {
+	logger := s.logFor(ctx)
+	if s.config.IsReadOnly() {
+		logger.Printf("[REMINDER_FAILED_KEPT] Reminder %s in job %s failed; the configuration is read-only, so the failure is not recorded in it", reminderID, jobID)
+		return
+	}
+	err := s.config.RecordReminderRun(jobID, reminderID, failure)
+	if errors.Is(err, config.ErrJobNotFound) {
+		logger.Printf("[REMINDER_JOB_GONE] Job %s was deleted while reminder %s ran, nothing to keep", jobID, reminderID)
+		return
+	}
+	if err != nil {
+		logger.Printf("[REMINDER_CLEANUP_ERROR] Failed to record the failure of reminder %s in job %s: %v", reminderID, jobID, err)
+		return
+	}
+	logger.Printf("[REMINDER_FAILED_KEPT] Keeping failed reminder %s in job %s for a retry", reminderID, jobID)
+
+	if err := s.config.Save(); err != nil {
+		logger.Printf("[REMINDER_SAVE_ERROR] Failed to save config after reminder %s failed: %v", reminderID, err)
+	}
+}
+
+// RetryReminder runs a failed reminder again now, synchronously, and
+// returns the run. When only the secondary failed, the primary is not sent
+// again and the secondary gets the primary's earlier response. Like any
+// reminder run it is deleted when it succeeds and kept with the new error
+// when it fails again.
+func (s *Scheduler) RetryReminder(jobID, reminderID string) (Execution, error) {
+	key := jobID + "_" + reminderID
+	s.mu.Lock()
+	if s.retryingReminders[key] {
+		s.mu.Unlock()
+		return Execution{}, fmt.Errorf("%w: %s", ErrReminderRetrying, reminderID)
+	}
+	s.retryingReminders[key] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.retryingReminders, key)
+		s.mu.Unlock()
+	}()
+
+	job, err := s.config.GetJob(jobID)
+	if err != nil {
+		return Execution{}, err
+	}
+
+	var reminder *config.Reminder
+	for i := range job.Reminders {
+		if job.Reminders[i].ID == reminderID {
+			reminder = &job.Reminders[i]
+			break
+		}
+	}
+	if reminder == nil {
+		return Execution{}, fmt.Errorf("%w: %s", ErrReminderNotFound, reminderID)
+	}
+	if reminder.LastStatus != StatusFailure {
+		return Execution{}, fmt.Errorf("%w: %s", ErrReminderNotFailed, reminderID)
+	}
+
+	release, err := s.acquireManualRun()
+	if err != nil {
+		return Execution{}, err
+	}
+	defer release()
+
+	s.logger.Printf("[REMINDER_RETRY] Retrying failed reminder %s for job %s", reminderID, jobID)
+	r := s.executeReminder(*job, *reminder, TriggerReminderRetry)
+	if r == nil {
+		return Execution{}, fmt.Errorf("%w: %s", config.ErrJobNotFound, jobID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.record, nil
+}
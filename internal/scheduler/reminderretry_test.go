@@ -0,0 +1,199 @@
+package scheduler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"cron-microservice/internal/config"
+)
+
+// switchServer answers with the status held in status and counts requests
+func switchServer(t *testing.T, status *atomic.Int64, requests *atomic.Int64) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(int(status.Load()))
+		w.Write([]byte(`{"message": "from primary"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// storedReminder returns the reminder r1 of job as stored in the config,
+// or nil when it was deleted
+func storedReminder(t *testing.T, s *Scheduler) *config.Reminder {
+	t.Helper()
+	job, err := s.config.GetJob("job")
+	if err != nil {
+		t.Fatalf("GetJob: %v", err)
+	}
+	for i := range job.Reminders {
+		if job.Reminders[i].ID == "r1" {
+			return &job.Reminders[i]
+		}
+	}
+	return nil
+}
+
+func TestRetryReminderAfterSecondaryFailure(t *testing.T) {
+	var primaryStatus, secondaryStatus, primaryRequests, secondaryRequests atomic.Int64
+	primaryStatus.Store(http.StatusOK)
+	secondaryStatus.Store(http.StatusInternalServerError)
+	primary := switchServer(t, &primaryStatus, &primaryRequests)
+	secondary := switchServer(t, &secondaryStatus, &secondaryRequests)
+
+	job, reminder := reminderJob(primary.URL)
+	job.Secondary = &config.WebhookConfig{URL: secondary.URL, Method: http.MethodPost, Enabled: true}
+	s, _ := newTestScheduler(t, job)
+
+	s.executeReminder(job, reminder, TriggerReminder)
+	kept := storedReminder(t, s)
+	if kept == nil {
+		t.Fatal("failed reminder was deleted")
+	}
+	if kept.FailedStep != config.ReminderStepSecondary {
+		t.Fatalf("failed step = %q, want the secondary", kept.FailedStep)
+	}
+	if response, ok := s.reminderResponse(job.ID, reminder.ID); !ok || response == "" {
+		t.Fatal("the primary's response was not kept for the retry")
+	}
+
+	secondaryStatus.Store(http.StatusOK)
+	execution, err := s.RetryReminder(job.ID, reminder.ID)
+	if err != nil {
+		t.Fatalf("RetryReminder: %v", err)
+	}
+	if execution.Status != StatusSuccess {
+		t.Errorf("retry status = %s, want %s", execution.Status, StatusSuccess)
+	}
+	if n := primaryRequests.Load(); n != 1 {
+		t.Errorf("primary sent %d times, want once", n)
+	}
+	if n := secondaryRequests.Load(); n != 2 {
+		t.Errorf("secondary sent %d times, want twice", n)
+	}
+	if storedReminder(t, s) != nil {
+		t.Error("reminder kept after a successful retry")
+	}
+}
+
+func TestReminderResponseNotSaved(t *testing.T) {
+	var primaryStatus, secondaryStatus, primaryRequests, secondaryRequests atomic.Int64
+	primaryStatus.Store(http.StatusOK)
+	secondaryStatus.Store(http.StatusInternalServerError)
+	primary := switchServer(t, &primaryStatus, &primaryRequests)
+	secondary := switchServer(t, &secondaryStatus, &secondaryRequests)
+
+	job, reminder := reminderJob(primary.URL)
+	job.Secondary = &config.WebhookConfig{URL: secondary.URL, Method: http.MethodPost, Enabled: true}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	cfg := config.New(path)
+	if err := cfg.AddJob(job); err != nil {
+		t.Fatalf("AddJob: %v", err)
+	}
+	s := New(cfg)
+	s.logger = log.New(io.Discard, "", 0)
+
+	s.executeReminder(job, reminder, TriggerReminder)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if regexp.MustCompile(`(?m)^\s+response:`).Match(data) {
+		t.Errorf("the primary's response was written to the config:\n%s", data)
+	}
+
+	// A restarted scheduler has no response to reuse and sends the primary
+	// again
+	restarted := New(s.config)
+	restarted.logger = s.logger
+	secondaryStatus.Store(http.StatusOK)
+	if _, err := restarted.RetryReminder(job.ID, reminder.ID); err != nil {
+		t.Fatalf("RetryReminder: %v", err)
+	}
+	if n := primaryRequests.Load(); n != 2 {
+		t.Errorf("primary sent %d times, want twice", n)
+	}
+}
+
+func TestKeepReminderResponseCapped(t *testing.T) {
+	s, _ := newTestScheduler(t)
+	if !s.keepReminderResponse("job", "r1", "ok") {
+		t.Error("a small response was not kept")
+	}
+	if s.keepReminderResponse("job", "r1", strings.Repeat("x", maxReminderResponseSize+1)) {
+		t.Error("a response over the cap was kept")
+	}
+	if _, ok := s.reminderResponse("job", "r1"); ok {
+		t.Error("an earlier response outlived one over the cap")
+	}
+}
+
+func TestRetryReminderAfterPrimaryFailure(t *testing.T) {
+	var status, requests atomic.Int64
+	status.Store(http.StatusInternalServerError)
+	server := switchServer(t, &status, &requests)
+
+	job, reminder := reminderJob(server.URL)
+	s, _ := newTestScheduler(t, job)
+
+	s.executeReminder(job, reminder, TriggerReminder)
+	if kept := storedReminder(t, s); kept == nil || kept.FailedStep != config.ReminderStepPrimary {
+		t.Fatalf("kept reminder = %+v, want one whose primary failed", kept)
+	}
+
+	status.Store(http.StatusOK)
+	if _, err := s.RetryReminder(job.ID, reminder.ID); err != nil {
+		t.Fatalf("RetryReminder: %v", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("primary sent %d times, want twice", n)
+	}
+}
+
+func TestRetryReminderRunsOnce(t *testing.T) {
+	var status, requests atomic.Int64
+	status.Store(http.StatusInternalServerError)
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) > 1 {
+			started <- struct{}{}
+			<-release
+		}
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+
+	job, reminder := reminderJob(server.URL)
+	s, _ := newTestScheduler(t, job)
+	s.executeReminder(job, reminder, TriggerReminder)
+
+	done := make(chan error)
+	go func() {
+		_, err := s.RetryReminder(job.ID, reminder.ID)
+		done <- err
+	}()
+	<-started
+
+	if _, err := s.RetryReminder(job.ID, reminder.ID); !errors.Is(err, ErrReminderRetrying) {
+		t.Errorf("concurrent retry: err = %v, want %v", err, ErrReminderRetrying)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("first retry: %v", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("webhook called %d times, want twice", n)
+	}
+}
@@ -0,0 +1,202 @@
+package scheduler
+
+import (
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+const (
+	// defaultReminderWindow is how far ahead reminder timers are armed
+	defaultReminderWindow = 24 * time.Hour
+
+	// defaultMaxReminderTimers caps the number of armed reminder timers
+	defaultMaxReminderTimers = 10000
+)
+
+// SetReminderWindow configures how far ahead reminder timers are armed and
+// how many may be armed at once. Reminders further out than the window, or
+// beyond the cap, stay in the config and are armed by a background sweep as
+// they come within range. A window of 0 arms every future reminder and a
+// maxTimers of 0 removes the cap. Call before Start.
+func (s *Scheduler) SetReminderWindow(window time.Duration, maxTimers int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reminderWindow = window
+	s.maxReminderTimers = maxTimers
+}
+
+// SetReminderJitter delays each reminder timer by a random amount up to
+// jitter past its datetime, so reminders due at the same moment don't all
+// call their webhooks at once. Reminders never fire early. 0 disables the
+// jitter. Call before Start.
+func (s *Scheduler) SetReminderJitter(jitter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reminderJitter = max(jitter, 0)
+}
+
+// jitterN returns a random duration in [0, n); tests replace it to pin the
+// reminder jitter
+var jitterN = rand.N[time.Duration]
+
+// jitterDelay picks a reminder's delay past its datetime, within
+// [0, reminderJitter]. The caller must hold s.mu.
+func (s *Scheduler) jitterDelay() time.Duration {
+	if s.reminderJitter <= 0 {
+		return 0
+	}
+	return jitterN(s.reminderJitter + 1)
+}
+
+// Reasons reminderDeferred gives for leaving a reminder unarmed
+const (
+	reminderOutsideWindow = "outside the reminder window"
+	reminderCapReached    = "reminder timer limit reached"
+)
+
+// reminderDeferred reports whether a reminder should be left unarmed for
+// now because it is outside the window or the timer cap is reached. The
+// caller must hold s.mu.
+func (s *Scheduler) reminderDeferred(datetime time.Time) (bool, string) {
+	if s.reminderWindow > 0 && time.Until(datetime) > s.reminderWindow {
+		return true, reminderOutsideWindow
+	}
+	if s.maxReminderTimers > 0 && len(s.reminders) >= s.maxReminderTimers {
+		return true, reminderCapReached
+	}
+	return false, ""
+}
+
+// latestArmedReminder returns the armed reminder due furthest out, if it is
+// due after datetime. The caller must hold s.mu.
+func (s *Scheduler) latestArmedReminder(datetime time.Time) (string, bool) {
+	latest, found := "", false
+	for key := range s.reminders {
+		due, ok := s.reminderDue[key]
+		if !ok || !due.After(datetime) {
+			continue
+		}
+		if !found || due.After(s.reminderDue[latest]) {
+			latest, found = key, true
+		}
+	}
+	return latest, found
+}
+
+// evictLaterReminder unarms the reminder due furthest out to make room
+// under the timer cap for one due at datetime, so the soonest reminders are
+// the ones armed. It reports whether a reminder was unarmed; the sweeper
+// arms it again once there is room. The caller must hold s.mu.
+func (s *Scheduler) evictLaterReminder(datetime time.Time) bool {
+	key, found := s.latestArmedReminder(datetime)
+	if !found {
+		return false
+	}
+
+	s.reminders[key].Stop()
+	delete(s.reminders, key)
+	delete(s.reminderDue, key)
+	s.cappedReminders[key] = true
+	s.logger.Printf("[REMINDER_DEFERRED] Reminder %s unarmed to make room for a sooner one: %s", key, reminderCapReached)
+	return true
+}
+
+// sweepInterval is how often deferred reminders are checked. It is half
+// the window so every reminder is armed well before it is due.
+func (s *Scheduler) sweepInterval() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.reminderWindow <= 0 && s.maxReminderTimers <= 0 {
+		return 0
+	}
+	if s.reminderWindow <= 0 {
+		return time.Minute
+	}
+	return s.reminderWindow / 2
+}
+
+// runReminderSweeper periodically arms deferred reminders until stop is closed
+func (s *Scheduler) runReminderSweeper(stop <-chan struct{}) {
+	interval := s.sweepInterval()
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.sweepReminders()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// sweepReminders arms reminders that have come within the window and are
+// not armed yet, soonest first. Reminders the timer cap held back until
+// they came due are fired late rather than skipped.
+func (s *Scheduler) sweepReminders() {
+	jobs := s.config.GetAllJobs()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type pending struct {
+		job      config.CronJob
+		reminder config.Reminder
+	}
+	var candidates []pending
+	now := time.Now()
+	for _, job := range jobs {
+		if job.Draft || job.Template {
+			continue
+		}
+		for _, reminder := range job.Reminders {
+			key := job.ID + "_" + reminder.ID
+			if !reminder.IsEnabled() || (!reminder.Datetime.After(now) && !s.cappedReminders[key]) {
+				continue
+			}
+			if _, exists := s.reminders[key]; exists {
+				continue
+			}
+			candidates = append(candidates, pending{job, reminder})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].reminder.Datetime.Before(candidates[j].reminder.Datetime)
+	})
+
+	armed := 0
+	for _, c := range candidates {
+		// Candidates are sorted, so once one can't be armed neither can
+		// any later one
+		if c.reminder.Datetime.After(now) {
+			if deferred, reason := s.reminderDeferred(c.reminder.Datetime); deferred {
+				if reason != reminderCapReached {
+					break
+				}
+				if _, found := s.latestArmedReminder(c.reminder.Datetime); !found {
+					break
+				}
+			}
+		}
+		if err := s.scheduleReminder(c.job, c.reminder); err != nil {
+			s.logger.Printf("[REMINDER_ERROR] Failed to schedule reminder %s for job %s: %v", c.reminder.ID, c.job.ID, err)
+			continue
+		}
+		armed++
+	}
+
+	if armed > 0 {
+		s.logger.Printf("[REMINDER_SWEEP] Armed %d deferred reminders", armed)
+	}
+}
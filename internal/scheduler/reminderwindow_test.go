@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+func TestJitterDelayBounds(t *testing.T) {
+	s, _ := newTestScheduler(t)
+	if got := s.jitterDelay(); got != 0 {
+		t.Errorf("without jitter: delay = %v, want 0", got)
+	}
+
+	s.SetReminderJitter(50 * time.Millisecond)
+	for i := 0; i < 1000; i++ {
+		if got := s.jitterDelay(); got < 0 || got > 50*time.Millisecond {
+			t.Fatalf("delay %v outside [0, 50ms]", got)
+		}
+	}
+}
+
+func TestReminderFiresWithinJitter(t *testing.T) {
+	const jitter = 200 * time.Millisecond
+	for name, pick := range map[string]func(time.Duration) time.Duration{
+		"earliest": func(time.Duration) time.Duration { return 0 },
+		"latest":   func(n time.Duration) time.Duration { return n - 1 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			original := jitterN
+			jitterN = pick
+			defer func() { jitterN = original }()
+
+			fired := make(chan time.Time, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fired <- time.Now()
+			}))
+			defer server.Close()
+
+			job, reminder := reminderJob(server.URL)
+			reminder.Datetime = time.Now().Add(100 * time.Millisecond)
+			job.Reminders[0] = reminder
+			s, _ := newTestScheduler(t, job)
+			s.SetReminderJitter(jitter)
+
+			s.mu.Lock()
+			err := s.scheduleReminder(job, reminder)
+			s.mu.Unlock()
+			if err != nil {
+				t.Fatalf("scheduleReminder: %v", err)
+			}
+
+			select {
+			case at := <-fired:
+				if at.Before(reminder.Datetime) {
+					t.Errorf("fired %v before its datetime", reminder.Datetime.Sub(at))
+				}
+				if late := at.Sub(reminder.Datetime); late > jitter+100*time.Millisecond {
+					t.Errorf("fired %v after its datetime, beyond the %v jitter", late, jitter)
+				}
+				if name == "latest" && at.Sub(reminder.Datetime) < jitter {
+					t.Errorf("fired %v after its datetime, want the full %v jitter", at.Sub(reminder.Datetime), jitter)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("reminder never fired")
+			}
+
+			// Let the run finish saving the config before the test ends
+			for deadline := time.Now().Add(2 * time.Second); len(s.History(job.ID)) == 0; time.Sleep(10 * time.Millisecond) {
+				if time.Now().After(deadline) {
+					t.Fatal("reminder run never finished")
+				}
+			}
+		})
+	}
+}
+
+func TestTimerCapArmsSoonestReminders(t *testing.T) {
+	job, later := reminderJob("http://127.0.0.1:1")
+	later.ID = "later"
+	later.Datetime = time.Now().Add(2 * time.Hour)
+	sooner := later
+	sooner.ID = "sooner"
+	sooner.Datetime = time.Now().Add(time.Hour)
+	// The later reminder comes first in config order
+	job.Reminders = []config.Reminder{later, sooner}
+	s, logs := newTestScheduler(t, job)
+	s.SetReminderWindow(0, 1)
+	defer s.removeJobReminders(job.ID)
+
+	s.mu.Lock()
+	err := s.scheduleReminder(job, later)
+	s.mu.Unlock()
+	if err != nil {
+		t.Fatalf("scheduleReminder: %v", err)
+	}
+
+	s.sweepReminders()
+	s.mu.RLock()
+	_, soonerArmed := s.reminders["job_sooner"]
+	_, laterArmed := s.reminders["job_later"]
+	laterCapped := s.cappedReminders["job_later"]
+	s.mu.RUnlock()
+	if !soonerArmed || laterArmed {
+		t.Errorf("armed sooner=%v later=%v, want only the sooner reminder\n%s", soonerArmed, laterArmed, logs.String())
+	}
+	if !laterCapped {
+		t.Error("the unarmed later reminder is not marked as held back by the cap")
+	}
+}
+
+func TestHeldBackReminderFiresLate(t *testing.T) {
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	job, reminder := reminderJob(server.URL)
+	s, logs := newTestScheduler(t, job)
+	s.SetReminderWindow(0, 1)
+
+	// Held back by the cap an hour before, the reminder has since come due
+	s.mu.Lock()
+	s.cappedReminders["job_r1"] = true
+	s.mu.Unlock()
+	reminder.Datetime = time.Now().Add(-time.Minute)
+	job.Reminders = []config.Reminder{reminder}
+	if err := s.config.AddJob(job); err != nil {
+		t.Fatalf("AddJob: %v", err)
+	}
+
+	s.sweepReminders()
+	waitFor(t, "the reminder to fire", func() bool { return len(s.History(job.ID)) > 0 })
+	if got := calls.Load(); got != 1 {
+		t.Errorf("webhook called %d times, want 1", got)
+	}
+	if !strings.Contains(logs.String(), "[REMINDER_LATE]") || strings.Contains(logs.String(), "[REMINDER_SKIPPED]") {
+		t.Errorf("held-back reminder not fired late:\n%s", logs.String())
+	}
+}
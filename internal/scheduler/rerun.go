@@ -0,0 +1,124 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+var (
+	// ErrNoSecondary is returned by RerunSecondary for a job without an
+	// enabled secondary webhook
+	ErrNoSecondary = errors.New("job has no enabled secondary webhook")
+
+	// ErrNoSavedOutput is returned by RerunSecondary when the job has no
+	// saved primary output to feed the secondary
+	ErrNoSavedOutput = errors.New("job has no saved output")
+)
+
+// RerunSecondaryResult is the outcome of re-running a job's secondary webhook
+type RerunSecondaryResult struct {
+	Execution Execution `json:"execution"`
+	Response  string    `json:"response,omitempty"` // Secondary webhook response
+}
+
+// RerunSecondary runs only a job's secondary webhook again, synchronously,
+// against the primary output saved by its last run. Variables are extracted
+// from that output afresh and the primary is not called. The run is recorded
+// in the history with the rerun-secondary trigger but never counts towards
+// auto-disable.
+func (s *Scheduler) RerunSecondary(jobID string) (RerunSecondaryResult, error) {
+	job, err := s.config.GetJob(jobID)
+	if err != nil {
+		return RerunSecondaryResult{}, err
+	}
+	if job.Secondary == nil || !job.Secondary.Enabled {
+		return RerunSecondaryResult{}, fmt.Errorf("%w: %s", ErrNoSecondary, jobID)
+	}
+
+	s.mu.RLock()
+	data := s.outputs[job.ID]
+	s.mu.RUnlock()
+	if data == "" {
+		return RerunSecondaryResult{}, fmt.Errorf("%w: %s", ErrNoSavedOutput, jobID)
+	}
+
+	release, err := s.acquireManualRun()
+	if err != nil {
+		return RerunSecondaryResult{}, err
+	}
+	defer release()
+
+	r, response := s.executeSecondaryOnly(*job, data)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return RerunSecondaryResult{Execution: r.record, Response: response}, nil
+}
+
+// executeSecondaryOnly runs a job's secondary webhook against data, the saved
+// primary output, and returns the completed run with the secondary response
+func (s *Scheduler) executeSecondaryOnly(job config.CronJob, data string) (result *run, response string) {
+	active := s.beginRun(job, TriggerRerunSecondary)
+	run, ctx := active.run, active.ctx
+	result = run
+	defer s.finishExecution(run)
+	defer active.cancel()
+	logger := s.logFor(ctx)
+	defer s.endRun(job.ID, run)
+
+	logger.Printf("[SECONDARY_RERUN] Re-running secondary webhook for job %s from saved output", job.ID)
+
+	job = withDefaultBodies(job)
+
+	scope := timeVariables(time.Now())
+	s.previousVariables.seed(job, scope)
+	s.seedOutputs(ctx, job, scope)
+	run.redact(scope)
+
+	if job.Secondary.ForEach != "" {
+		s.executeSecondaryForEach(ctx, job, data, scope)
+	} else {
+		secondary := *job.Secondary
+
+		if len(secondary.JQSelectors) > 0 {
+			extractionStart := time.Now()
+			vars, err := s.extractVariables(ctx, data, secondary.JQSelectors)
+			run.timing(func(t *Timing) { t.ExtractionMs = milliseconds(time.Since(extractionStart)) })
+			if err != nil {
+				logger.Printf("[JQ_ERROR] Failed to extract variables: %v", err)
+			} else {
+				mergeVariables(scope, vars)
+			}
+		}
+
+		// As in a normal run, the saved output is the body unless a
+		// template is set
+		secondary.Body = data
+		if secondary.BodyTemplate != "" {
+			processedBody, err := s.renderTemplate(ctx, secondary, secondary.BodyTemplate, scope)
+			if err != nil {
+				logger.Printf("[TEMPLATE_ERROR] Failed to process template: %v", err)
+			} else {
+				secondary.Body = processedBody
+			}
+		}
+
+		if !s.skipForEmptyVariables(ctx, job.ID, secondary, scope) {
+			secondaryStart := time.Now()
+			var err error
+			response, err = s.executeWebhook(withStep(ctx, job.ID, "secondary"), s.renderRequest(ctx, secondary, scope))
+			run.timing(func(t *Timing) { t.SecondaryMs = milliseconds(time.Since(secondaryStart)) })
+			if err != nil {
+				run.fail(err)
+				logger.Printf("[SECONDARY_WEBHOOK_ERROR] Failed to execute secondary webhook for job %s: %v", job.ID, err)
+			} else {
+				logger.Printf("[SECONDARY_WEBHOOK_SUCCESS] Secondary webhook executed successfully for job %s", job.ID)
+			}
+		}
+	}
+
+	return result, response
+}
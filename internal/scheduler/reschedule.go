@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"fmt"
+	"reflect"
+
+	"cron-microservice/internal/config"
+)
+
+// scheduleOnlyChange reports whether updated differs from previous in its
+// schedule alone
+func scheduleOnlyChange(previous, updated config.CronJob) bool {
+	previous.Schedule = updated.Schedule
+	previous.Interval = updated.Interval
+	return reflect.DeepEqual(previous, updated)
+}
+
+// reschedule rebuilds only a job's cron entry, keeping its runtime state:
+// the consecutive failure count, suspension timer, cached enabled check
+// and reminders carry over, as do history and saved output, which never
+// depend on the entry. A temporary schedule override ends, as with any
+// update. The caller must hold s.mu.
+func (s *Scheduler) reschedule(job config.CronJob) error {
+	if entryID, exists := s.jobs[job.ID]; exists {
+		s.cron.Remove(entryID)
+		delete(s.jobs, job.ID)
+	}
+	s.clearOverride(job.ID)
+
+	if job.Draft || job.Template || !job.Enabled || job.AfterJob != "" {
+		return nil
+	}
+
+	if err := ResolveSchedule(&job); err != nil {
+		return fmt.Errorf("failed to parse interval: %w", err)
+	}
+
+	entryID, err := s.addCronEntry(job, job.Schedule)
+	if err != nil {
+		return fmt.Errorf("failed to add cron job: %w", err)
+	}
+	s.jobs[job.ID] = entryID
+
+	s.logger.Printf("[JOB_RESCHEDULED] Job %s now runs on %q", job.ID, job.Schedule)
+	return nil
+}
@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleOnlyChangeKeepsRuntimeState(t *testing.T) {
+	job, reminder := reminderJob("http://example.com")
+	job.Schedule = "@every 1h"
+	job.Enabled = true
+	suspendedUntil := time.Now().Add(time.Hour)
+	job.SuspendedUntil = &suspendedUntil
+
+	s, _ := newTestScheduler(t, job)
+	if err := s.LoadJobs(); err != nil {
+		t.Fatalf("LoadJobs: %v", err)
+	}
+
+	s.mu.Lock()
+	s.failures[job.ID] = 2
+	entry := s.jobs[job.ID]
+	suspension := s.suspensions[job.ID]
+	timer := s.reminders[job.ID+"_"+reminder.ID]
+	s.mu.Unlock()
+	if suspension == nil || timer == nil {
+		t.Fatal("the job's suspension or reminder was not set up")
+	}
+
+	rescheduled := job
+	rescheduled.Schedule = "@every 2h"
+	if err := s.AddJob(rescheduled); err != nil {
+		t.Fatalf("AddJob: %v", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.jobs[job.ID] == entry {
+		t.Error("the job kept its old cron entry")
+	}
+	if got := s.failures[job.ID]; got != 2 {
+		t.Errorf("failure count %d after a schedule-only change, want 2", got)
+	}
+	if s.suspensions[job.ID] != suspension {
+		t.Error("the suspension timer was replaced")
+	}
+	if s.reminders[job.ID+"_"+reminder.ID] != timer {
+		t.Error("the reminder timer was replaced")
+	}
+}
+
+func TestOtherChangeResetsRuntimeState(t *testing.T) {
+	job, reminder := reminderJob("http://example.com")
+	job.Schedule = "@every 1h"
+	job.Enabled = true
+
+	s, _ := newTestScheduler(t, job)
+	if err := s.LoadJobs(); err != nil {
+		t.Fatalf("LoadJobs: %v", err)
+	}
+
+	s.mu.Lock()
+	s.failures[job.ID] = 2
+	timer := s.reminders[job.ID+"_"+reminder.ID]
+	s.mu.Unlock()
+
+	renamed := job
+	renamed.Name = "renamed"
+	if err := s.AddJob(renamed); err != nil {
+		t.Fatalf("AddJob: %v", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if got := s.failures[job.ID]; got != 0 {
+		t.Errorf("failure count %d after a full update, want 0", got)
+	}
+	if s.reminders[job.ID+"_"+reminder.ID] == timer {
+		t.Error("the reminder timer was kept across a full update")
+	}
+}
@@ -0,0 +1,224 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRetryBackoff is the delay before the first retry when a webhook
+	// does not set retry_backoff; it doubles on every further attempt
+	defaultRetryBackoff = time.Second
+
+	// maxRetryBackoff caps the doubled backoff, however many retries a
+	// webhook allows
+	maxRetryBackoff = time.Hour
+
+	// retryBudgetRatio is the number of retry tokens earned per successful
+	// request, capping retries at roughly 10% of successful traffic
+	retryBudgetRatio = 0.1
+
+	// retryBudgetMin is the reserve of retries available even when no
+	// requests have succeeded yet
+	retryBudgetMin = 10
+
+	// retryBudgetMax caps how many tokens can accumulate during quiet periods
+	retryBudgetMax = 100
+
+	// maxErrorBodySize caps the response body kept in a statusError, and so
+	// in error messages and {{error.body}}
+	maxErrorBodySize = 4096
+)
+
+// statusError is returned by sendWebhook when the target responds with an
+// error status
+type statusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *statusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("webhook returned error status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("webhook returned error status %d: %s", e.StatusCode, e.Body)
+}
+
+// newStatusError returns a statusError with the body capped at
+// maxErrorBodySize bytes
+func newStatusError(statusCode int, body string) *statusError {
+	if len(body) > maxErrorBodySize {
+		body = strings.ToValidUTF8(body[:maxErrorBodySize], "") + "..."
+	}
+	return &statusError{StatusCode: statusCode, Body: body}
+}
+
+// retryResponseError is returned when a successful response matches the
+// webhook's RetryIfJQ condition
+type retryResponseError struct {
+	Expression string
+	Body       string
+}
+
+func (e *retryResponseError) Error() string {
+	return fmt.Sprintf("webhook response matched retry_if_jq '%s': %s", e.Expression, e.Body)
+}
+
+// isRetryable reports whether a failed attempt may be retried. Transport
+// failures, 429, 5xx responses, and responses matching RetryIfJQ are
+// retryable; other errors are not. DNS failures follow their own policy,
+// see dnsRetryable.
+func isRetryable(err error, retryOnDNS bool) bool {
+	var responseErr *retryResponseError
+	if errors.As(err, &responseErr) {
+		return true
+	}
+
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrHostDenied) {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsRetryable(dnsErr, retryOnDNS)
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && urlErr.Op != "parse"
+}
+
+// dnsRetryable reports whether a failed lookup may succeed on another try.
+// A timeout or a resolver answering SERVFAIL is often transient and is
+// retried like any transport failure. A name that does not exist
+// (NXDOMAIN) usually won't start existing between attempts, so it is only
+// retried when the webhook opts in with retryNotFound.
+func dnsRetryable(err *net.DNSError, retryNotFound bool) bool {
+	if err.IsNotFound {
+		return retryNotFound
+	}
+	return true
+}
+
+// dnsFailure describes a failed lookup for the logs, or returns "" when err
+// is not a DNS error
+func dnsFailure(err error) string {
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) {
+		return ""
+	}
+	switch {
+	case dnsErr.IsNotFound:
+		return "name not found"
+	case dnsErr.IsTimeout:
+		return "lookup timed out"
+	case dnsErr.IsTemporary:
+		return "temporary resolver failure"
+	default:
+		return "lookup failed"
+	}
+}
+
+// RetryBudget is a shared token bucket that caps retries across all jobs to
+// a fraction of successful requests, so an outage can't turn into a retry
+// storm. Each success deposits retryBudgetRatio tokens and each retry
+// withdraws one.
+type RetryBudget struct {
+	mu      sync.Mutex
+	tokens  float64
+	shed    int64
+	retries int64
+}
+
+// RetryBudgetStatus is a snapshot of the retry budget
+type RetryBudgetStatus struct {
+	Tokens  float64 `json:"tokens"`
+	Retries int64   `json:"retries"`
+	Shed    int64   `json:"shed"`
+}
+
+func newRetryBudget() *RetryBudget {
+	return &RetryBudget{tokens: retryBudgetMin}
+}
+
+// recordSuccess credits the budget for a successful request
+func (b *RetryBudget) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens += retryBudgetRatio
+	if b.tokens > retryBudgetMax {
+		b.tokens = retryBudgetMax
+	}
+}
+
+// tryRetry withdraws a token for a retry, returning false when the budget
+// is exhausted and the retry must be shed
+func (b *RetryBudget) tryRetry() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens < 1 {
+		b.shed++
+		return false
+	}
+
+	b.tokens--
+	b.retries++
+	return true
+}
+
+func (b *RetryBudget) status() RetryBudgetStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return RetryBudgetStatus{
+		Tokens:  b.tokens,
+		Retries: b.retries,
+		Shed:    b.shed,
+	}
+}
+
+// Jitter strategies for WebhookConfig.RetryJitter
+const (
+	JitterNone  = "none"
+	JitterFull  = "full"
+	JitterEqual = "equal"
+)
+
+// retryDelay returns the exponential backoff before the given retry
+// (1-based), randomized by the jitter strategy. Full jitter picks a delay
+// in [0, backoff), equal jitter in [backoff/2, backoff); none or an empty
+// strategy keeps the exact backoff. The backoff never exceeds
+// maxRetryBackoff.
+func retryDelay(base time.Duration, retry int, jitter string) time.Duration {
+	if base <= 0 {
+		base = defaultRetryBackoff
+	}
+	backoff := min(base, maxRetryBackoff)
+	for i := 1; i < retry && backoff < maxRetryBackoff; i++ {
+		backoff = min(backoff*2, maxRetryBackoff)
+	}
+
+	switch jitter {
+	case JitterFull:
+		return time.Duration(rand.Int64N(int64(backoff)))
+	case JitterEqual:
+		half := backoff / 2
+		return half + time.Duration(rand.Int64N(int64(backoff-half)))
+	default:
+		return backoff
+	}
+}
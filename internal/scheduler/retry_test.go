@@ -0,0 +1,132 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+func TestRetryDelayWithoutJitter(t *testing.T) {
+	base := 100 * time.Millisecond
+	for retry, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		5: 1600 * time.Millisecond,
+	} {
+		if got := retryDelay(base, retry, JitterNone); got != want {
+			t.Errorf("retry %d: delay = %v, want %v", retry, got, want)
+		}
+	}
+	if got := retryDelay(0, 1, ""); got != defaultRetryBackoff {
+		t.Errorf("default base: delay = %v, want %v", got, defaultRetryBackoff)
+	}
+}
+
+func TestRetryDelayJitterBounds(t *testing.T) {
+	base := 100 * time.Millisecond
+	for _, retry := range []int{1, 3, 6} {
+		backoff := retryDelay(base, retry, JitterNone)
+		for i := 0; i < 1000; i++ {
+			if got := retryDelay(base, retry, JitterFull); got < 0 || got >= backoff {
+				t.Fatalf("full jitter, retry %d: delay %v outside [0, %v)", retry, got, backoff)
+			}
+			if got := retryDelay(base, retry, JitterEqual); got < backoff/2 || got >= backoff {
+				t.Fatalf("equal jitter, retry %d: delay %v outside [%v, %v)", retry, got, backoff/2, backoff)
+			}
+		}
+	}
+}
+
+func TestRetryDelayFullJitterSpreads(t *testing.T) {
+	// Over many draws full jitter should land in every quarter of the range
+	backoff := retryDelay(time.Second, 1, JitterNone)
+	var quarters [4]int
+	for i := 0; i < 1000; i++ {
+		quarters[retryDelay(time.Second, 1, JitterFull)*4/backoff]++
+	}
+	for i, n := range quarters {
+		if n == 0 {
+			t.Errorf("no delay fell in quarter %d of the backoff: %v", i, quarters)
+		}
+	}
+}
+
+func TestRetryDelayCapped(t *testing.T) {
+	for _, retry := range []int{40, 64, 65, 1000} {
+		for _, jitter := range []string{JitterNone, JitterFull, JitterEqual} {
+			got := retryDelay(time.Second, retry, jitter)
+			if got < 0 || got > maxRetryBackoff {
+				t.Errorf("retry %d, jitter %q: delay %v outside [0, %v]", retry, jitter, got, maxRetryBackoff)
+			}
+		}
+	}
+	if got := retryDelay(time.Second, 1000, JitterNone); got != maxRetryBackoff {
+		t.Errorf("retry 1000: delay = %v, want %v", got, maxRetryBackoff)
+	}
+}
+
+// stubResolver makes every request of s fail to resolve with err, counting
+// the lookups
+func stubResolver(s *Scheduler, err *net.DNSError) *atomic.Int64 {
+	var lookups atomic.Int64
+	s.httpClient = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			lookups.Add(1)
+			return nil, err
+		},
+	}}
+	return &lookups
+}
+
+func TestDNSRetries(t *testing.T) {
+	servfail := &net.DNSError{Err: "server misbehaving", Name: "api.example.com", IsTemporary: true}
+	timeout := &net.DNSError{Err: "i/o timeout", Name: "api.example.com", IsTimeout: true}
+	nxdomain := &net.DNSError{Err: "no such host", Name: "api.example.com", IsNotFound: true}
+
+	tests := []struct {
+		name       string
+		err        *net.DNSError
+		retryOnDNS bool
+		lookups    int64
+	}{
+		{"servfail", servfail, false, 3},
+		{"timeout", timeout, false, 3},
+		{"nxdomain", nxdomain, false, 1},
+		{"servfail with retry_on_dns", servfail, true, 3},
+		{"nxdomain with retry_on_dns", nxdomain, true, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, logs := newTestScheduler(t)
+			lookups := stubResolver(s, tt.err)
+
+			_, err := s.executeWebhook(context.Background(), config.WebhookConfig{
+				URL:          "http://api.example.com/",
+				Method:       http.MethodGet,
+				Retries:      2,
+				RetryBackoff: 1,
+				RetryOnDNS:   tt.retryOnDNS,
+				Enabled:      true,
+			})
+
+			var dnsErr *net.DNSError
+			if !errors.As(err, &dnsErr) {
+				t.Fatalf("err = %v, want a DNS error", err)
+			}
+			if got := lookups.Load(); got != tt.lookups {
+				t.Errorf("lookups = %d, want %d", got, tt.lookups)
+			}
+			if !strings.Contains(logs.String(), "[WEBHOOK_DNS_ERROR]") {
+				t.Errorf("DNS failure not logged:\n%s", logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"cron-microservice/internal/config"
+)
+
+type stepKey struct{}
+
+// withStep tags ctx with the job and step a webhook call belongs to, so
+// per-step state such as round-robin position can be tracked
+func withStep(ctx context.Context, jobID, step string) context.Context {
+	return context.WithValue(ctx, stepKey{}, jobID+"/"+step)
+}
+
+// stepFrom returns the job/step tag carried by ctx, if any
+func stepFrom(ctx context.Context) string {
+	step, _ := ctx.Value(stepKey{}).(string)
+	return step
+}
+
+// roundRobin implements smooth weighted round-robin selection, keeping the
+// running weights for each job step between executions
+type roundRobin struct {
+	mu      sync.Mutex
+	current map[string][]int
+}
+
+func newRoundRobin() *roundRobin {
+	return &roundRobin{current: make(map[string][]int)}
+}
+
+// next picks the URL to call for the given step. Weights are matched to
+// URLs by index; missing or non-positive weights count as 1.
+func (rr *roundRobin) next(key string, webhook config.WebhookConfig) string {
+	if len(webhook.URLs) == 0 {
+		return webhook.URL
+	}
+	if len(webhook.URLs) == 1 {
+		return webhook.URLs[0]
+	}
+
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	current := rr.current[key]
+	if len(current) != len(webhook.URLs) {
+		current = make([]int, len(webhook.URLs))
+	}
+
+	total, best := 0, 0
+	for i := range webhook.URLs {
+		weight := 1
+		if i < len(webhook.Weights) && webhook.Weights[i] > 0 {
+			weight = webhook.Weights[i]
+		}
+		current[i] += weight
+		total += weight
+		if current[i] > current[best] {
+			best = i
+		}
+	}
+	current[best] -= total
+
+	rr.current[key] = current
+	return webhook.URLs[best]
+}
+
+// forget drops the round-robin state for every step of a job
+func (rr *roundRobin) forget(jobID string) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	for key := range rr.current {
+		if strings.HasPrefix(key, jobID+"/") {
+			delete(rr.current, key)
+		}
+	}
+}
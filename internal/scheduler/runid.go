@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"cron-microservice/internal/config"
+)
+
+type runKey struct{}
+
+// newRunID returns a short random identifier for a single execution
+func newRunID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// withRun attaches an in-progress execution to ctx
+func withRun(ctx context.Context, r *run) context.Context {
+	return context.WithValue(ctx, runKey{}, r)
+}
+
+// runContext returns the context for a run, carrying the run
+func (s *Scheduler) runContext(job config.CronJob, r *run) (context.Context, context.CancelFunc) {
+	r.mu.Lock()
+	r.sensitive = job.SensitiveVariables
+	r.mu.Unlock()
+	return context.WithCancel(withRun(context.Background(), r))
+}
+
+// runFrom returns the execution carried by ctx, or nil
+func runFrom(ctx context.Context) *run {
+	r, _ := ctx.Value(runKey{}).(*run)
+	return r
+}
+
+// runIDFrom returns the run ID of the execution carried by ctx, if any
+func runIDFrom(ctx context.Context) string {
+	if r := runFrom(ctx); r != nil {
+		return r.record.ID
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// runLogger writes through the scheduler's logger, prefixing each line with
+// the job and run it belongs to so one execution can be followed with grep
+type runLogger struct {
+	logger *log.Logger
+	prefix string
+	run    *run // Masks the run's sensitive values, if set
+}
+
+func (l runLogger) Printf(format string, v ...interface{}) {
+	if l.run == nil {
+		l.logger.Printf(l.prefix+format, v...)
+		return
+	}
+	l.logger.Print(l.run.mask(fmt.Sprintf(l.prefix+format, v...)))
+}
+
+// logFor returns a logger tagging lines with "[job/run]" when ctx carries
+// an execution, using the first 8 characters of the run ID
+func (s *Scheduler) logFor(ctx context.Context) runLogger {
+	r := runFrom(ctx)
+	if r == nil {
+		return runLogger{logger: s.logger}
+	}
+
+	runID := r.record.ID
+	if len(runID) > 8 {
+		runID = runID[:8]
+	}
+	return runLogger{logger: s.logger, prefix: "[" + r.record.JobID + "/" + runID + "] ", run: r}
+}
@@ -0,0 +1,33 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"cron-microservice/internal/config"
+)
+
+// scheduleHorizon is how far ahead a schedule must fire at least once. Leap
+// days fire within four years; the cron parser itself gives up after five.
+const scheduleHorizon = 4 * 366 * 24 * time.Hour
+
+// ScheduleWarnings reports a job schedule that parses but never fires, such
+// as "0 0 30 2 *" (February 30). Schedules that don't parse are left to
+// validation.
+func ScheduleWarnings(job config.CronJob) []string {
+	if err := ResolveSchedule(&job); err != nil || job.Schedule == "" {
+		return nil
+	}
+	parsed, err := cron.ParseStandard(job.Schedule)
+	if err != nil {
+		return nil
+	}
+
+	now := time.Now().In(config.DefaultLocation())
+	if next := parsed.Next(now); next.IsZero() || next.Sub(now) > scheduleHorizon {
+		return []string{fmt.Sprintf("schedule %q never fires within the next 4 years", job.Schedule)}
+	}
+	return nil
+}
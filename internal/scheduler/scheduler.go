@@ -4,56 +4,160 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"math/rand/v2"
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/itchyny/gojq"
 	"github.com/robfig/cron/v3"
 	"cron-microservice/internal/config"
+	"cron-microservice/internal/version"
 )
 
 type Scheduler struct {
-	cron       *cron.Cron
-	jobs       map[string]cron.EntryID
-	config     *config.Config
-	httpClient *http.Client
-	mu         sync.RWMutex
-	outputs    map[string]string // Store outputs from webhook calls
-	logger     *log.Logger
-	reminders  map[string]*time.Timer // Store timers for reminders
+	cron              *cron.Cron
+	jobs              map[string]cron.EntryID
+	config            *config.Config
+	httpClient        *http.Client
+	mu                sync.RWMutex
+	outputs           map[string]string // Store outputs from webhook calls
+	logger            *log.Logger
+	reminders         map[string]*time.Timer                 // Store timers for reminders
+	retryingReminders map[string]bool                        // Reminders with a retry in progress
+	reminderResponses map[string]string                      // Primary responses of reminders whose secondary failed, for a retry
+	heldReminders     map[string]heldReminder                // Reminders that came due while paused, fired on resume
+	roundRobin        *roundRobin                            // Weighted round-robin state for multi-URL webhooks
+	retryBudget       *RetryBudget                           // Shared cap on retries across all webhooks
+	clients           *clients                               // Cached clients for webhooks needing custom transports
+	history           *history                               // Recent executions per job
+	reminderWindow    time.Duration                          // Only reminders due within this window get timers
+	maxReminderTimers int                                    // Cap on armed reminder timers
+	reminderDue       map[string]time.Time                   // Datetime of each armed reminder, to make room for sooner ones under the cap
+	cappedReminders   map[string]bool                        // Reminders held back by the timer cap, fired late rather than skipped once due
+	reminderJitter    time.Duration                          // Upper bound on the random delay added to reminder timers
+	stopSweeper       chan struct{}                          // Closed by Stop to end the reminder sweeper
+	warmupConcurrency int                                    // Concurrent warmup requests in LoadJobs, 0 disables warmup
+	paused            atomic.Bool                            // When set, cron ticks and reminders are skipped
+	skippedTicks      atomic.Int64                           // Ticks skipped while paused
+	failures          map[string]int                         // Consecutive failed runs per job
+	overrides         map[string]*scheduleOverride           // Temporary schedules replacing a job's persisted one
+	counters          executionCounters                      // Executions since start, for Stats
+	startedAt         time.Time                              // When the scheduler was created
+	active            map[string]map[*run]context.CancelFunc // In-progress job runs, for coalescing
+	responseHashes    *responseHashes                        // Last response per notify_on_change job
+	maxVariableSize   int                                    // Cap on extracted variable size in bytes, 0 means none
+	omitContentType   bool                                   // When set, requests only carry a configured Content-Type
+	hostPolicy        *HostPolicy                            // Hosts webhooks may call, nil allows all
+	suspensions       map[string]*suspension                 // Timers clearing each job's SuspendedUntil
+	enabledChecks     *enabledChecks                         // Cached feature flag answers per job
+	previousVariables *previousVariables                     // Selector variables from each job's last successful run
+	monitors          *monitorStates                         // Whether each monitor job was down after its last scheduled probe
+	loaded            map[string]config.CronJob              // Version of each job last added, for reconciling reloads
+	sloWindow         time.Duration                          // Default window for SLO data, 0 means one hour
+	maxManualRuns     int                                    // Cap on manual runs in progress, 0 means none
+	manualRuns        atomic.Int64                           // Manual runs in progress
 }
 
 func New(cfg *config.Config) *Scheduler {
 	return &Scheduler{
-		cron: cron.New(),
-		jobs: make(map[string]cron.EntryID),
+		cron:   cron.New(cron.WithLocation(config.DefaultLocation())),
+		jobs:   make(map[string]cron.EntryID),
 		config: cfg,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		outputs: make(map[string]string),
-		logger:  log.New(log.Writer(), "[SCHEDULER] ", log.LstdFlags),
-		reminders: make(map[string]*time.Timer),
+		outputs:           make(map[string]string),
+		logger:            log.New(log.Writer(), "[SCHEDULER] ", log.LstdFlags),
+		reminders:         make(map[string]*time.Timer),
+		retryingReminders: make(map[string]bool),
+		reminderResponses: make(map[string]string),
+		heldReminders:     make(map[string]heldReminder),
+		roundRobin:        newRoundRobin(),
+		retryBudget:       newRetryBudget(),
+		clients:           newClients(),
+		history:           newHistory(defaultHistorySize),
+		reminderWindow:    defaultReminderWindow,
+		maxReminderTimers: defaultMaxReminderTimers,
+		reminderDue:       make(map[string]time.Time),
+		cappedReminders:   make(map[string]bool),
+		maxManualRuns:     defaultMaxManualRuns,
+		stopSweeper:       make(chan struct{}),
+		failures:          make(map[string]int),
+		overrides:         make(map[string]*scheduleOverride),
+		startedAt:         time.Now(),
+		maxVariableSize:   defaultMaxVariableSize,
+		active:            make(map[string]map[*run]context.CancelFunc),
+		responseHashes:    newResponseHashes(),
+		suspensions:       make(map[string]*suspension),
+		enabledChecks:     newEnabledChecks(),
+		previousVariables: newPreviousVariables(),
+		monitors:          newMonitorStates(),
+		loaded:            make(map[string]config.CronJob),
 	}
 }
 
 func (s *Scheduler) Start() {
 	s.cron.Start()
+	go s.runReminderSweeper(s.stopSweeper)
 }
 
 func (s *Scheduler) Stop() {
 	s.cron.Stop()
+	close(s.stopSweeper)
+}
+
+// Shutdown stops scheduling new runs and waits up to timeout for in-flight
+// executions to finish. It reports whether they all finished in time.
+func (s *Scheduler) Shutdown(timeout time.Duration) bool {
+	s.PauseAll()
+	s.Stop()
+
+	deadline := time.Now().Add(timeout)
+	for s.counters.inFlight.Load() > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return true
 }
 
 func (s *Scheduler) AddJob(job config.CronJob) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.addJob(job)
+}
+
+// addJob schedules a job, replacing any earlier version of it. The caller
+// must hold s.mu.
+func (s *Scheduler) addJob(job config.CronJob) (err error) {
+	previous, known := s.loaded[job.ID]
+
+	// Only a job that was scheduled counts as loaded, so that a reload
+	// retries one that failed. The old entry is gone either way.
+	loaded := job
+	defer func() {
+		if err != nil {
+			delete(s.loaded, job.ID)
+			return
+		}
+		s.loaded[job.ID] = loaded
+	}()
+
+	// A job whose schedule alone changed keeps its runtime state and only
+	// gets a new cron entry
+	if known && scheduleOnlyChange(previous, job) {
+		return s.reschedule(job)
+	}
+
 	// Remove existing job if it exists
 	if entryID, exists := s.jobs[job.ID]; exists {
 		s.cron.Remove(entryID)
@@ -63,24 +167,64 @@ func (s *Scheduler) AddJob(job config.CronJob) error {
 	// Remove existing reminders for this job
 	s.removeJobReminders(job.ID)
 
-	// If job is disabled, don't schedule it (just remove if it existed)
-	if !job.Enabled {
-		return nil
-	}
+	// Any update, including re-enabling, starts the failure count over
+	delete(s.failures, job.ID)
 
-	action := func() {
-		s.executeJob(job)
+	// An update also ends any temporary schedule override
+	s.clearOverride(job.ID)
+	s.clearSuspension(job.ID)
+	s.enabledChecks.forget(job.ID)
+
+	for _, warning := range ScheduleWarnings(job) {
+		s.logger.Printf("[JOB_SCHEDULE_WARNING] Job %s: %s", job.ID, warning)
+	}
+	for _, warning := range TemplateWarnings(job) {
+		s.logger.Printf("[JOB_TEMPLATE_WARNING] Job %s: %s", job.ID, warning)
+	}
+	if len(job.OutputsFrom) > 0 {
+		for _, warning := range OutputsFromWarnings(job, s.config.GetAllJobs()) {
+			s.logger.Printf("[JOB_TEMPLATE_WARNING] Job %s: %s", job.ID, warning)
+		}
 	}
 
-	entryID, err := s.cron.AddFunc(job.Schedule, action)
-	if err != nil {
-		return fmt.Errorf("failed to add cron job: %w", err)
+	// Drafts and templates are never scheduled, neither their cron entry
+	// nor their reminders
+	if job.Draft || job.Template {
+		return nil
 	}
 
-	s.jobs[job.ID] = entryID
+	// Clear the suspension once it passes, whether or not the job is enabled
+	s.trackSuspension(job)
+
+	// Only enabled jobs get a cron entry, and chained jobs are run by the
+	// job they follow instead
+	if job.Enabled && job.AfterJob != "" {
+		offset, err := job.AfterOffset()
+		if err != nil {
+			return err
+		}
+		s.logger.Printf("[JOB_CHAINED] Job %s runs %v after job %s fires", job.ID, offset, job.AfterJob)
+	} else if job.Enabled {
+		if err := ResolveSchedule(&job); err != nil {
+			return fmt.Errorf("failed to parse interval: %w", err)
+		}
 
-	// Schedule reminders for this job
+		entryID, err := s.addCronEntry(job, job.Schedule)
+		if err != nil {
+			return fmt.Errorf("failed to add cron job: %w", err)
+		}
+
+		s.jobs[job.ID] = entryID
+	}
+
+	// Schedule reminders for this job. Unless the job's reminder policy
+	// cascades, reminders are independent of the job's enabled state and
+	// are only skipped when disabled themselves.
 	for _, reminder := range job.Reminders {
+		if !reminder.IsEnabled() {
+			s.logger.Printf("[REMINDER_DISABLED] Reminder %s for job %s is disabled, skipping", reminder.ID, job.ID)
+			continue
+		}
 		if err := s.scheduleReminder(job, reminder); err != nil {
 			s.logger.Printf("[REMINDER_ERROR] Failed to schedule reminder %s for job %s: %v", reminder.ID, job.ID, err)
 		}
@@ -93,16 +237,104 @@ func (s *Scheduler) RemoveJob(jobID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.removeJob(jobID)
+	return nil
+}
+
+// removeJob unschedules a job and drops its state. The caller must hold
+// s.mu.
+func (s *Scheduler) removeJob(jobID string) {
+	delete(s.loaded, jobID)
+
+	// Jobs chained after this one have nothing left to run them
+	for id, job := range s.loaded {
+		if job.AfterJob == jobID {
+			s.logger.Printf("[JOB_CHAIN_ORPHANED] Job %s runs after job %s, which was removed; it won't run until after_job is changed", id, jobID)
+		}
+	}
+
 	if entryID, exists := s.jobs[jobID]; exists {
 		s.cron.Remove(entryID)
 		delete(s.jobs, jobID)
 		delete(s.outputs, jobID)
 	}
 
-	// Remove reminders for this job
+	s.roundRobin.forget(jobID)
+	s.history.forget(jobID)
+	s.responseHashes.forget(jobID)
+	s.clearOverride(jobID)
+	s.clearSuspension(jobID)
+	s.enabledChecks.forget(jobID)
+	s.previousVariables.forget(jobID)
+	s.monitors.forget(jobID)
+
+	// Remove reminders for this job, and the responses kept for their retry
 	s.removeJobReminders(jobID)
+	for key := range s.reminderResponses {
+		if strings.HasPrefix(key, jobID+"_") {
+			delete(s.reminderResponses, key)
+		}
+	}
+	for key := range s.cappedReminders {
+		if strings.HasPrefix(key, jobID+"_") {
+			delete(s.cappedReminders, key)
+		}
+	}
+}
 
-	return nil
+// scheduledAction returns the function a job's cron entry runs. due, when
+// set, returns when the tick being run was due.
+func (s *Scheduler) scheduledAction(job config.CronJob, due func() time.Time) func() {
+	return func() {
+		// Every tick takes its due time, skipped or not, to stay in step
+		// with cron
+		var scheduled time.Time
+		if due != nil {
+			scheduled = due()
+		}
+
+		// Chained jobs follow the schedule firing, whether or not this
+		// tick goes on to run
+		s.armFollowers(job.ID)
+
+		if s.skipIfPaused("job", job.ID) {
+			return
+		}
+		if !scheduled.IsZero() && s.tooLate(job, scheduled) {
+			return
+		}
+		if suspended(job) {
+			s.logger.Printf("[JOB_SUSPENDED_SKIP] Skipping tick for job %s, suspended until %s", job.ID, job.SuspendedUntil.Format(time.RFC3339))
+			return
+		}
+		if until, quiet := job.QuietHours.Until(time.Now()); quiet {
+			s.logger.Printf("[JOB_QUIET_SKIP] Skipping tick for job %s during quiet hours until %s", job.ID, until.Format(time.RFC3339))
+			return
+		}
+		if !sampledIn(job.SampleRate) {
+			s.logger.Printf("[JOB_SAMPLED_OUT] Skipping tick for job %s (sample rate %v)", job.ID, *job.SampleRate)
+			return
+		}
+		if !s.enabledByFlag(job) {
+			s.logger.Printf("[JOB_FLAG_OFF] Skipping tick for job %s, its enabled check is off", job.ID)
+			return
+		}
+		active := s.coalesce(job)
+		if active == nil {
+			return
+		}
+		s.executeRun(job, nil, active)
+	}
+}
+
+// sampledIn rolls a scheduled tick against a job's sample rate. Jobs
+// without a rate always fire; rates at or above 1 always fire and rates at
+// or below 0 never do.
+func sampledIn(rate *float64) bool {
+	if rate == nil {
+		return true
+	}
+	return rand.Float64() < *rate
 }
 
 // removeJobReminders removes all reminders for a job
@@ -112,6 +344,7 @@ func (s *Scheduler) removeJobReminders(jobID string) {
 		if strings.HasPrefix(reminderID, jobID+"_") {
 			timer.Stop()
 			delete(s.reminders, reminderID)
+			delete(s.reminderDue, reminderID)
 		}
 	}
 }
@@ -126,132 +359,231 @@ func (s *Scheduler) removeReminder(jobID, reminderID string) {
 		timer.Stop()
 		delete(s.reminders, reminderKey)
 	}
+	delete(s.reminderDue, reminderKey)
+	delete(s.cappedReminders, reminderKey)
+	delete(s.reminderResponses, reminderKey)
 }
 
 // scheduleReminder schedules a reminder to be executed at its specified time
 func (s *Scheduler) scheduleReminder(job config.CronJob, reminder config.Reminder) error {
-	now := time.Now()
-	if reminder.Datetime.Before(now) {
-		// Reminder is in the past, don't schedule it
-		s.logger.Printf("[REMINDER_SKIPPED] Reminder %s is in the past, skipping", reminder.ID)
+	// Drafts and templates never run on their own, reminders included
+	if job.Draft || job.Template {
+		s.logger.Printf("[REMINDER_SKIPPED] Reminder %s for job %s not armed, the job is a %s", reminder.ID, job.ID, job.State())
 		return nil
 	}
 
-	duration := reminder.Datetime.Sub(now)
+	key := job.ID + "_" + reminder.ID
+	now := time.Now()
+	if !reminder.Datetime.After(now) {
+		if !s.cappedReminders[key] {
+			// Reminder is in the past, don't schedule it
+			s.logger.Printf("[REMINDER_SKIPPED] Reminder %s is in the past, skipping", reminder.ID)
+			return nil
+		}
+		// Held back by the timer cap until it came due, so it fires late
+		// instead of being lost
+		s.logger.Printf("[REMINDER_LATE] Reminder %s for job %s came due while held back by the reminder timer limit, firing it now", reminder.ID, job.ID)
+	} else if deferred, reason := s.reminderDeferred(reminder.Datetime); deferred {
+		// Under the cap, a sooner reminder takes the slot of a later one
+		if reason != reminderCapReached || !s.evictLaterReminder(reminder.Datetime) {
+			// The sweeper arms it once it comes within range
+			if reason == reminderCapReached {
+				s.cappedReminders[key] = true
+			}
+			s.logger.Printf("[REMINDER_DEFERRED] Reminder %s for job %s not armed yet: %s", reminder.ID, job.ID, reason)
+			return nil
+		}
+	}
+
+	// Jitter only ever delays a reminder past its datetime
+	jitter := s.jitterDelay()
+	duration := max(reminder.Datetime.Sub(now), 0) + jitter
 
-	action := func() {
-		s.executeReminder(job, reminder)
+	var action func()
+	action = func() {
+		if s.holdIfPaused(job, reminder, action) {
+			return
+		}
+		if reason := reminderSuppressed(job); reason != "" {
+			s.mu.Lock()
+			delete(s.reminders, job.ID+"_"+reminder.ID)
+			delete(s.reminderDue, job.ID+"_"+reminder.ID)
+			s.mu.Unlock()
+			s.logger.Printf("[REMINDER_SUPPRESSED] Skipping reminder %s for job %s: %s", reminder.ID, job.ID, reason)
+			return
+		}
+		if s.deferUntilQuietHoursEnd(job, reminder, action) {
+			return
+		}
+		s.executeReminder(job, reminder, TriggerReminder)
 	}
 
 	timer := time.AfterFunc(duration, action)
-	s.reminders[job.ID+"_"+reminder.ID] = timer
+	s.reminders[key] = timer
+	s.reminderDue[key] = reminder.Datetime
+	delete(s.cappedReminders, key)
 
-	s.logger.Printf("[REMINDER_SCHEDULED] Scheduled reminder %s for job %s in %v", reminder.ID, job.ID, duration)
+	if jitter > 0 {
+		s.logger.Printf("[REMINDER_SCHEDULED] Scheduled reminder %s for job %s in %v (%v jitter)", reminder.ID, job.ID, duration, jitter.Round(time.Millisecond))
+	} else {
+		s.logger.Printf("[REMINDER_SCHEDULED] Scheduled reminder %s for job %s in %v", reminder.ID, job.ID, duration)
+	}
 	return nil
 }
 
-// executeReminder executes a reminder by sending a webhook
-func (s *Scheduler) executeReminder(job config.CronJob, reminder config.Reminder) {
+// executeReminder executes a reminder by sending a webhook. A reminder that
+// succeeds is deleted; one that fails is kept with its error so it can be
+// retried. The returned run is nil when the job no longer exists.
+func (s *Scheduler) executeReminder(job config.CronJob, reminder config.Reminder, trigger string) (result *run) {
+	// The job may have been deleted after the timer fired but before
+	// RemoveJob could stop it
+	if _, err := s.config.GetJob(job.ID); err != nil {
+		s.mu.Lock()
+		delete(s.reminders, job.ID+"_"+reminder.ID)
+		delete(s.reminderDue, job.ID+"_"+reminder.ID)
+		s.mu.Unlock()
+		s.logger.Printf("[REMINDER_JOB_GONE] Job %s was deleted, dropping reminder %s", job.ID, reminder.ID)
+		return nil
+	}
+
 	s.logger.Printf("[REMINDER_START] Executing reminder: %s for job: %s", reminder.Text, job.Name)
 
-	// Create a temporary webhook config for the reminder based on the primary webhook
+	run := s.startExecution(job.ID, trigger)
+	result = run
+	defer s.finishExecution(run)
+	ctx, cancel := s.runContext(job, run)
+	defer cancel()
+	logger := s.logFor(ctx)
+
+	// Create a temporary webhook config for the reminder based on its own
+	// webhook, falling back to the job's primary webhook
 	reminderWebhook := job.Primary
+	if reminder.Webhook != nil {
+		reminderWebhook = *reminder.Webhook
+		logger.Printf("[REMINDER_WEBHOOK] Using reminder-specific webhook for reminder %s", reminder.ID)
+	}
+	if body := defaultBody(job, reminderWebhook); body != "" {
+		reminderWebhook.Body = body
+		logger.Printf("[REMINDER_DEFAULT_BODY] Using the job's default body template for reminder %s", reminder.ID)
+	}
+
+	// Variables accumulate in this scope across the steps of the reminder
+	scope := timeVariables(time.Now())
+	scope["REMINDER"] = reminder.Text
+
+	// The step that failed first, kept with a failed reminder so a retry
+	// after a secondary failure doesn't send the primary again
+	var failedStep string
 
-	// Process the body template with the REMINDER variable
-	if reminderWebhook.Body != "" {
-		variables := map[string]interface{}{
-			"REMINDER": reminder.Text,
+	var primaryResponse string
+	retrySecondary := false
+	if trigger == TriggerReminderRetry && reminder.FailedStep == config.ReminderStepSecondary {
+		primaryResponse, retrySecondary = s.reminderResponse(job.ID, reminder.ID)
+		if !retrySecondary {
+			logger.Printf("[REMINDER_RETRY_PRIMARY] The primary response of reminder %s is no longer kept, sending the primary again", reminder.ID)
+		}
+	}
+	if retrySecondary {
+		logger.Printf("[REMINDER_RETRY_SECONDARY] Primary webhook for reminder %s succeeded before, retrying the secondary only", reminder.ID)
+	} else {
+		// Process the body template with the REMINDER variable
+		if reminderWebhook.Body != "" {
+			processedBody, err := s.renderTemplate(ctx, reminderWebhook, reminderWebhook.Body, scope)
+			if err != nil {
+				logger.Printf("[REMINDER_ERROR] Failed to process template for reminder %s: %v", reminder.ID, err)
+				// Fall back to original body
+			} else {
+				reminderWebhook.Body = processedBody
+				logger.Printf("[REMINDER_TEMPLATE] Processed template: %s", processedBody)
+			}
 		}
 
-		processedBody, err := s.processTemplate(reminderWebhook.Body, variables)
+		// Execute the primary webhook for the reminder and capture response
+		primaryStart := time.Now()
+		response, err := s.executeWebhook(withStep(ctx, job.ID, "reminder"), s.renderRequest(ctx, reminderWebhook, scope))
+		run.timing(func(t *Timing) { t.PrimaryMs = milliseconds(time.Since(primaryStart)) })
+		primaryResponse = response
 		if err != nil {
-			s.logger.Printf("[REMINDER_ERROR] Failed to process template for reminder %s: %v", reminder.ID, err)
-			// Fall back to original body
+			run.fail(err)
+			failedStep = config.ReminderStepPrimary
+			logger.Printf("[REMINDER_ERROR] Failed to execute primary webhook for reminder %s: %v", reminder.ID, err)
 		} else {
-			reminderWebhook.Body = processedBody
-			s.logger.Printf("[REMINDER_TEMPLATE] Processed template: %s", processedBody)
+			logger.Printf("[REMINDER_PRIMARY_SUCCESS] Primary webhook for reminder %s executed successfully", reminder.ID)
+			logger.Printf("[REMINDER_PRIMARY_RESPONSE] Response: %s", primaryResponse)
 		}
 	}
 
-	// Execute the primary webhook for the reminder and capture response
-	ctx := context.Background()
-	primaryResponse, err := s.executeWebhook(ctx, reminderWebhook)
-	if err != nil {
-		s.logger.Printf("[REMINDER_ERROR] Failed to execute primary webhook for reminder %s: %v", reminder.ID, err)
-	} else {
-		s.logger.Printf("[REMINDER_PRIMARY_SUCCESS] Primary webhook for reminder %s executed successfully", reminder.ID)
-		s.logger.Printf("[REMINDER_PRIMARY_RESPONSE] Response: %s", primaryResponse)
-	}
-
 	// Execute secondary webhook if configured and enabled
 	if job.Secondary != nil && job.Secondary.Enabled {
-		s.logger.Printf("[REMINDER_SECONDARY] Preparing secondary webhook for reminder %s", reminder.ID)
+		logger.Printf("[REMINDER_SECONDARY] Preparing secondary webhook for reminder %s", reminder.ID)
 
-		// Create a copy of secondary config
+		// Create a copy of secondary config, falling back to the job's
+		// default body template
 		secondaryWebhook := *job.Secondary
+		if body := defaultBody(job, secondaryWebhook); body != "" {
+			secondaryWebhook.BodyTemplate = body
+		}
 
 		// For reminders, we want to process the secondary webhook similar to regular jobs
 		// We'll use the primary response as data for the secondary webhook
 		if primaryResponse != "" {
-			s.logger.Printf("[REMINDER_SECONDARY] Processing primary response: %s", primaryResponse)
+			logger.Printf("[REMINDER_SECONDARY] Processing primary response: %s", primaryResponse)
 
 			// Log the JQ selectors configuration
-			s.logger.Printf("[REMINDER_DEBUG] Job secondary JQ selectors: %+v", job.Secondary.JQSelectors)
-			s.logger.Printf("[REMINDER_DEBUG] Job secondary JQ selectors length: %d", len(job.Secondary.JQSelectors))
+			logger.Printf("[REMINDER_DEBUG] Job secondary JQ selectors: %+v", job.Secondary.JQSelectors)
+			logger.Printf("[REMINDER_DEBUG] Job secondary JQ selectors length: %d", len(job.Secondary.JQSelectors))
 
 			// Extract variables using jq selectors if configured
-			var variables map[string]interface{}
 			if len(job.Secondary.JQSelectors) > 0 {
-				s.logger.Printf("[REMINDER_JQ_EXTRACTION] Extracting variables using jq selectors")
-				vars, err := s.extractVariables(primaryResponse, job.Secondary.JQSelectors)
+				logger.Printf("[REMINDER_JQ_EXTRACTION] Extracting variables using jq selectors")
+				extractionStart := time.Now()
+				vars, err := s.extractVariables(ctx, primaryResponse, job.Secondary.JQSelectors)
+				run.timing(func(t *Timing) { t.ExtractionMs = milliseconds(time.Since(extractionStart)) })
 				if err != nil {
-					s.logger.Printf("[REMINDER_JQ_ERROR] Failed to extract variables: %v", err)
+					logger.Printf("[REMINDER_JQ_ERROR] Failed to extract variables: %v", err)
 				} else {
-					variables = vars
-					s.logger.Printf("[REMINDER_JQ_SUCCESS] Extracted %d variables", len(variables))
+					mergeVariables(scope, vars)
+					logger.Printf("[REMINDER_JQ_SUCCESS] Extracted %d variables", len(vars))
 					// Log extracted variables
-					for k, v := range variables {
-						s.logger.Printf("[REMINDER_JQ_VARIABLE] %s = %v", k, v)
+					for k, v := range vars {
+						logger.Printf("[REMINDER_JQ_VARIABLE] %s = %v", k, v)
 					}
 				}
 			} else {
-				s.logger.Printf("[REMINDER_JQ_SKIP] No JQ selectors configured for secondary webhook")
+				logger.Printf("[REMINDER_JQ_SKIP] No JQ selectors configured for secondary webhook")
 			}
 
 			// Add the reminder text as a special variable
-			if variables == nil {
-				variables = make(map[string]interface{})
-			}
-			variables["REMINDER"] = reminder.Text
+			scope["REMINDER"] = reminder.Text
 
 			// Only add message variable with the full primary response if it wasn't already extracted by JQ
-			if _, exists := variables["message"]; !exists {
-				variables["message"] = primaryResponse
-				s.logger.Printf("[REMINDER_MESSAGE_VAR] Setting message variable to primary response as fallback")
+			if _, exists := scope["message"]; !exists {
+				scope["message"] = primaryResponse
+				logger.Printf("[REMINDER_MESSAGE_VAR] Setting message variable to primary response as fallback")
 			} else {
-				s.logger.Printf("[REMINDER_MESSAGE_VAR] Keeping JQ-extracted message variable")
+				logger.Printf("[REMINDER_MESSAGE_VAR] Keeping JQ-extracted message variable")
 			}
 
 			// If template is provided, process it with extracted variables
 			if secondaryWebhook.BodyTemplate != "" {
-				s.logger.Printf("[REMINDER_SECONDARY_TEMPLATE] Processing template: %s", secondaryWebhook.BodyTemplate)
-				processedBody, err := s.processTemplate(secondaryWebhook.BodyTemplate, variables)
+				logger.Printf("[REMINDER_SECONDARY_TEMPLATE] Processing template: %s", secondaryWebhook.BodyTemplate)
+				processedBody, err := s.renderTemplate(ctx, secondaryWebhook, secondaryWebhook.BodyTemplate, scope)
 				if err != nil {
-					s.logger.Printf("[REMINDER_SECONDARY_TEMPLATE_ERROR] Failed to process template for reminder %s: %v", reminder.ID, err)
+					logger.Printf("[REMINDER_SECONDARY_TEMPLATE_ERROR] Failed to process template for reminder %s: %v", reminder.ID, err)
 					// Fall back to using primary response directly in body
 					secondaryWebhook.Body = primaryResponse
 				} else {
 					secondaryWebhook.Body = processedBody
-					s.logger.Printf("[REMINDER_SECONDARY_TEMPLATE_SUCCESS] Processed template result: %s", processedBody)
+					logger.Printf("[REMINDER_SECONDARY_TEMPLATE_SUCCESS] Processed template result: %s", processedBody)
 				}
 			} else if secondaryWebhook.Body != "" {
 				// If there's a body but no template, process it with variables
-				processedBody, err := s.processTemplate(secondaryWebhook.Body, variables)
+				processedBody, err := s.renderTemplate(ctx, secondaryWebhook, secondaryWebhook.Body, scope)
 				if err != nil {
-					s.logger.Printf("[REMINDER_SECONDARY_BODY_ERROR] Failed to process body for reminder %s: %v", reminder.ID, err)
+					logger.Printf("[REMINDER_SECONDARY_BODY_ERROR] Failed to process body for reminder %s: %v", reminder.ID, err)
 				} else {
 					secondaryWebhook.Body = processedBody
-					s.logger.Printf("[REMINDER_SECONDARY_BODY_SUCCESS] Processed body: %s", processedBody)
+					logger.Printf("[REMINDER_SECONDARY_BODY_SUCCESS] Processed body: %s", processedBody)
 				}
 			} else {
 				// Default to using the primary response as body
@@ -259,137 +591,282 @@ func (s *Scheduler) executeReminder(job config.CronJob, reminder config.Reminder
 			}
 		} else {
 			// No primary response, use reminder text as fallback
-			variables := map[string]interface{}{
-				"REMINDER": reminder.Text,
-				"message":  reminder.Text,
-			}
+			scope["message"] = reminder.Text
 
 			// Process template or body with reminder text
 			if secondaryWebhook.BodyTemplate != "" {
-				s.logger.Printf("[REMINDER_SECONDARY_TEMPLATE] Processing template with reminder text: %s", secondaryWebhook.BodyTemplate)
-				processedBody, err := s.processTemplate(secondaryWebhook.BodyTemplate, variables)
+				logger.Printf("[REMINDER_SECONDARY_TEMPLATE] Processing template with reminder text: %s", secondaryWebhook.BodyTemplate)
+				processedBody, err := s.renderTemplate(ctx, secondaryWebhook, secondaryWebhook.BodyTemplate, scope)
 				if err != nil {
-					s.logger.Printf("[REMINDER_SECONDARY_TEMPLATE_ERROR] Failed to process template for reminder %s: %v", reminder.ID, err)
+					logger.Printf("[REMINDER_SECONDARY_TEMPLATE_ERROR] Failed to process template for reminder %s: %v", reminder.ID, err)
 					// Fall back to using reminder text directly in body
-					secondaryWebhook.Body = fmt.Sprintf("{\"reminder\": \"%s\", \"message\": \"%s\"}", reminder.Text, reminder.Text)
+					secondaryWebhook.Body = reminderFallbackBody(reminder)
 				} else {
 					secondaryWebhook.Body = processedBody
-					s.logger.Printf("[REMINDER_SECONDARY_TEMPLATE_SUCCESS] Processed template result: %s", processedBody)
+					logger.Printf("[REMINDER_SECONDARY_TEMPLATE_SUCCESS] Processed template result: %s", processedBody)
 				}
 			} else if secondaryWebhook.Body != "" {
 				// If there's a body but no template, process it with reminder text
-				processedBody, err := s.processTemplate(secondaryWebhook.Body, variables)
+				processedBody, err := s.renderTemplate(ctx, secondaryWebhook, secondaryWebhook.Body, scope)
 				if err != nil {
-					s.logger.Printf("[REMINDER_SECONDARY_BODY_ERROR] Failed to process body for reminder %s: %v", reminder.ID, err)
+					logger.Printf("[REMINDER_SECONDARY_BODY_ERROR] Failed to process body for reminder %s: %v", reminder.ID, err)
 				} else {
 					secondaryWebhook.Body = processedBody
-					s.logger.Printf("[REMINDER_SECONDARY_BODY_SUCCESS] Processed body: %s", processedBody)
+					logger.Printf("[REMINDER_SECONDARY_BODY_SUCCESS] Processed body: %s", processedBody)
 				}
 			} else {
 				// Default body with just the reminder text
-				secondaryWebhook.Body = fmt.Sprintf("{\"reminder\": \"%s\", \"message\": \"%s\"}", reminder.Text, reminder.Text)
+				secondaryWebhook.Body = reminderFallbackBody(reminder)
 			}
 		}
 
 		// Execute the secondary webhook
-		if _, err := s.executeWebhook(ctx, secondaryWebhook); err != nil {
-			s.logger.Printf("[REMINDER_SECONDARY_ERROR] Failed to execute secondary webhook for reminder %s: %v", reminder.ID, err)
-		} else {
-			s.logger.Printf("[REMINDER_SECONDARY_SUCCESS] Secondary webhook for reminder %s executed successfully", reminder.ID)
+		if !s.skipForEmptyVariables(ctx, job.ID, secondaryWebhook, scope) {
+			secondaryStart := time.Now()
+			_, err := s.executeWebhook(withStep(ctx, job.ID, "secondary"), s.renderRequest(ctx, secondaryWebhook, scope))
+			run.timing(func(t *Timing) { t.SecondaryMs = milliseconds(time.Since(secondaryStart)) })
+			if err != nil {
+				run.fail(err)
+				if failedStep == "" {
+					failedStep = config.ReminderStepSecondary
+				}
+				logger.Printf("[REMINDER_SECONDARY_ERROR] Failed to execute secondary webhook for reminder %s: %v", reminder.ID, err)
+			} else {
+				logger.Printf("[REMINDER_SECONDARY_SUCCESS] Secondary webhook for reminder %s executed successfully", reminder.ID)
+			}
 		}
 	} else if job.Secondary != nil {
-		s.logger.Printf("[REMINDER_SECONDARY_DISABLED] Secondary webhook is disabled for reminder %s", reminder.ID)
+		logger.Printf("[REMINDER_SECONDARY_DISABLED] Secondary webhook is disabled for reminder %s", reminder.ID)
 	} else {
-		s.logger.Printf("[REMINDER_NO_SECONDARY] No secondary webhook configured for reminder %s", reminder.ID)
+		logger.Printf("[REMINDER_NO_SECONDARY] No secondary webhook configured for reminder %s", reminder.ID)
 	}
 
 	// Clean up the timer
 	s.mu.Lock()
 	delete(s.reminders, job.ID+"_"+reminder.ID)
+	delete(s.reminderDue, job.ID+"_"+reminder.ID)
 	s.mu.Unlock()
 
-	// Delete the reminder from the job configuration
-	if err := s.config.DeleteReminder(job.ID, reminder.ID); err != nil {
-		s.logger.Printf("[REMINDER_CLEANUP_ERROR] Failed to delete reminder %s from job %s: %v", reminder.ID, job.ID, err)
+	// Keep a failed reminder with its error instead of losing it
+	run.mu.Lock()
+	status, lastErr := run.record.Status, run.record.Error
+	run.mu.Unlock()
+	if status == StatusFailure {
+		failure := config.ReminderRun{Status: status, Error: lastErr, FailedStep: failedStep, At: time.Now()}
+		if failedStep == config.ReminderStepSecondary {
+			if !s.keepReminderResponse(job.ID, reminder.ID, primaryResponse) {
+				logger.Printf("[REMINDER_RESPONSE_TOO_LARGE] The primary response of reminder %s is over %d bytes and not kept, a retry sends the primary again", reminder.ID, maxReminderResponseSize)
+			}
+		} else {
+			// A failure outside the webhooks, such as the run's timeout,
+			// retries the whole reminder
+			failure.FailedStep = config.ReminderStepPrimary
+			s.forgetReminderResponse(job.ID, reminder.ID)
+		}
+		s.keepFailedReminder(ctx, job.ID, reminder.ID, failure)
+		return
+	}
+	s.forgetReminderResponse(job.ID, reminder.ID)
+
+	// A read-only config keeps the reminder; being in the past, it is not
+	// armed again
+	if s.config.IsReadOnly() {
+		logger.Printf("[REMINDER_KEPT_READ_ONLY] Reminder %s of job %s ran; the configuration is read-only, so it stays in it", reminder.ID, job.ID)
+		return
+	}
+
+	// Delete the reminder from the job configuration. A job deleted while
+	// the reminder ran took the reminder with it.
+	if err := s.config.DeleteReminder(job.ID, reminder.ID); errors.Is(err, config.ErrJobNotFound) {
+		logger.Printf("[REMINDER_JOB_GONE] Job %s was deleted while reminder %s ran, nothing to clean up", job.ID, reminder.ID)
+	} else if err != nil {
+		logger.Printf("[REMINDER_CLEANUP_ERROR] Failed to delete reminder %s from job %s: %v", reminder.ID, job.ID, err)
 	} else {
-		s.logger.Printf("[REMINDER_DELETED] Successfully deleted reminder %s from job %s", reminder.ID, job.ID)
+		logger.Printf("[REMINDER_DELETED] Successfully deleted reminder %s from job %s", reminder.ID, job.ID)
 
 		// Save the updated configuration
 		if err := s.config.Save(); err != nil {
-			s.logger.Printf("[REMINDER_SAVE_ERROR] Failed to save config after deleting reminder %s: %v", reminder.ID, err)
+			logger.Printf("[REMINDER_SAVE_ERROR] Failed to save config after deleting reminder %s: %v", reminder.ID, err)
 		} else {
-			s.logger.Printf("[REMINDER_CONFIG_SAVED] Configuration saved after deleting reminder %s", reminder.ID)
+			logger.Printf("[REMINDER_CONFIG_SAVED] Configuration saved after deleting reminder %s", reminder.ID)
 		}
 	}
+	return
 }
 
-func (s *Scheduler) executeJob(job config.CronJob) {
-	ctx := context.Background()
+// executeJob runs a job once. vars seeds the variable scope and may be nil.
+// The returned run is complete once executeJob returns.
+func (s *Scheduler) executeJob(job config.CronJob, trigger string, vars map[string]interface{}) *run {
+	return s.executeRun(job, vars, s.beginRun(job, trigger))
+}
 
-	s.logger.Printf("[JOB_START] Executing job: %s (ID: %s)", job.Name, job.ID)
+// executeRun carries out a run already started and registered by beginRun
+// or coalesce. The returned run is complete once executeRun returns.
+func (s *Scheduler) executeRun(job config.CronJob, vars map[string]interface{}, active *activeRun) (result *run) {
+	run, trigger := active.run, active.run.record.Trigger
+	result = run
+	defer s.finishExecution(run)
+	if trigger != TriggerInvoke {
+		defer s.trackFailures(job, run)
+	}
+	ctx, cancel := active.ctx, active.cancel
+	defer cancel()
+	logger := s.logFor(ctx)
+	defer s.endRun(job.ID, run)
+
+	logger.Printf("[JOB_START] Executing job: %s (ID: %s, run: %s, trigger: %s)", job.Name, job.ID, run.record.ID, trigger)
+
+	// Webhooks without a body of their own use the job's default template
+	job = withDefaultBodies(job)
+
+	// Variables accumulate in this scope across the steps of the execution,
+	// starting from the current time, the previous run's variables, other
+	// jobs' saved outputs and any invoke variables
+	scope := timeVariables(time.Now())
+	s.previousVariables.seed(job, scope)
+	s.seedOutputs(ctx, job, scope)
+	mergeVariables(scope, vars)
+	run.redact(scope)
+	defer s.recordPreviousVariables(ctx, job, scope)
+
+	// Run the precheck and only continue when its condition holds
+	if job.Precheck != nil {
+		precheckStart := time.Now()
+		proceed, vars := s.runPrecheck(ctx, job)
+		run.timing(func(t *Timing) { t.PrecheckMs = milliseconds(time.Since(precheckStart)) })
+		if !proceed {
+			return
+		}
+		mergeVariables(scope, vars)
+	}
 
 	// Execute primary webhook
-	s.logger.Printf("[PRIMARY_WEBHOOK] Sending %s request to %s", job.Primary.Method, job.Primary.URL)
+	logger.Printf("[PRIMARY_WEBHOOK] Sending %s request to %s", job.Primary.Method, job.Primary.URL)
 	if job.Primary.Body != "" {
-		s.logger.Printf("[PRIMARY_WEBHOOK] Request body: %s", job.Primary.Body)
+		logger.Printf("[PRIMARY_WEBHOOK] Request body: %s", job.Primary.Body)
+	}
+
+	// With a body template or placeholders in the body the primary body is
+	// rendered like a template
+	primary := job.Primary
+	if primary.BodyTemplate != "" || placeholderPattern.MatchString(primary.Body) {
+		body := primary.Body
+		if primary.BodyTemplate != "" {
+			body = primary.BodyTemplate
+		}
+		processedBody, err := s.renderTemplate(ctx, primary, body, scope)
+		if err != nil {
+			logger.Printf("[PRIMARY_WEBHOOK_TEMPLATE_ERROR] Failed to process body for job %s: %v", job.ID, err)
+		} else {
+			primary.Body = processedBody
+		}
+	}
+
+	// Monitors only probe the primary and track whether it is up
+	if job.Monitor {
+		s.executeMonitor(ctx, job, s.renderRequest(ctx, primary, scope), scope)
+		return
 	}
 
-	output, err := s.executeWebhook(ctx, job.Primary)
+	// Proxy-style jobs pipe the primary response into the secondary
+	// without buffering it
+	if job.StreamToSecondary && job.Secondary != nil && job.Secondary.Enabled {
+		s.executeStreamed(ctx, job, s.renderRequest(ctx, primary, scope), scope)
+		return
+	}
+
+	primaryStart := time.Now()
+	output, err := s.executeWebhook(withStep(ctx, job.ID, "primary"), s.renderRequest(ctx, primary, scope))
+	run.timing(func(t *Timing) { t.PrimaryMs = milliseconds(time.Since(primaryStart)) })
 	if err != nil {
-		s.logger.Printf("[PRIMARY_WEBHOOK_ERROR] Failed to execute primary webhook for job %s: %v", job.ID, err)
+		run.fail(err)
+		logger.Printf("[PRIMARY_WEBHOOK_ERROR] Failed to execute primary webhook for job %s: %v", job.ID, err)
+		if job.OnPrimaryFailure != nil {
+			s.executeFailureWebhook(ctx, job, err, scope)
+		}
 		return
 	}
 
-	s.logger.Printf("[PRIMARY_WEBHOOK_SUCCESS] Primary webhook executed successfully for job %s", job.ID)
-	s.logger.Printf("[PRIMARY_WEBHOOK_RESPONSE] Response: %s", output)
+	run.output = output
+	logger.Printf("[PRIMARY_WEBHOOK_SUCCESS] Primary webhook executed successfully for job %s", job.ID)
+	logger.Printf("[PRIMARY_WEBHOOK_RESPONSE] Response: %s", output)
+
+	// Save output if configured, reshaped by the primary's transform. A
+	// HEAD primary has no body, so there is never anything to save. The
+	// secondary sees the output as saved.
+	saveOutput := job.SaveOutput && !isHead(job.Primary.Method)
+	secondaryData := output
+	if saveOutput && output != "" {
+		saved := output
+		if job.Primary.OutputTransform != "" {
+			transformed, err := transformOutput(ctx, job.Primary.OutputTransform, output)
+			if err != nil {
+				logger.Printf("[OUTPUT_TRANSFORM_ERROR] Saving the raw response for job %s: %v", job.ID, err)
+			} else {
+				logger.Printf("[OUTPUT_TRANSFORMED] Transformed output for job %s from %d to %d bytes", job.ID, len(output), len(transformed))
+				saved = transformed
+			}
+		}
 
-	// Save output if configured
-	if job.SaveOutput && output != "" {
 		s.mu.Lock()
-		s.outputs[job.ID] = output
+		s.outputs[job.ID] = saved
 		s.mu.Unlock()
-		s.logger.Printf("[OUTPUT_SAVED] Saved output for job %s: %s", job.ID, output)
-	} else if job.SaveOutput {
-		s.logger.Printf("[OUTPUT_EMPTY] No output to save for job %s", job.ID)
+		secondaryData = saved
+		logger.Printf("[OUTPUT_SAVED] Saved output for job %s: %s", job.ID, saved)
+	} else if saveOutput {
+		logger.Printf("[OUTPUT_EMPTY] No output to save for job %s", job.ID)
+	}
+
+	// Change detection: only continue when the response differs from the
+	// previous run's, and only take it as notified once the run succeeds
+	if job.NotifyOnChange {
+		if !s.responseChanged(ctx, job.ID, output, scope) {
+			return
+		}
+		defer s.commitResponse(ctx, job.ID, output)
 	}
 
 	// Execute secondary webhook if configured and enabled
 	if job.Secondary != nil {
 		if !job.Secondary.Enabled {
-			s.logger.Printf("[SECONDARY_WEBHOOK_DISABLED] Secondary webhook is disabled for job %s", job.ID)
+			logger.Printf("[SECONDARY_WEBHOOK_DISABLED] Secondary webhook is disabled for job %s", job.ID)
 			return
 		}
 
-		s.logger.Printf("[SECONDARY_WEBHOOK] Preparing secondary webhook for job %s", job.ID)
-		s.logger.Printf("[SECONDARY_WEBHOOK_DETAILS] URL: %s, Method: %s", job.Secondary.URL, job.Secondary.Method)
+		logger.Printf("[SECONDARY_WEBHOOK] Preparing secondary webhook for job %s", job.ID)
+		logger.Printf("[SECONDARY_WEBHOOK_DETAILS] URL: %s, Method: %s", job.Secondary.URL, job.Secondary.Method)
 
 		// Log headers if present
 		if len(job.Secondary.Headers) > 0 {
-			s.logger.Printf("[SECONDARY_WEBHOOK_HEADERS] Headers: %+v", job.Secondary.Headers)
+			logger.Printf("[SECONDARY_WEBHOOK_HEADERS] Headers: %+v", job.Secondary.Headers)
 		}
 
-		// If we have saved output, use it as data for secondary webhook
-		if job.SaveOutput {
+		// Fan out over an array in the response, or use saved output as
+		// data for the secondary webhook
+		if job.Secondary.ForEach != "" {
+			s.executeSecondaryForEach(ctx, job, secondaryData, scope)
+		} else if saveOutput {
 			s.mu.RLock()
 			data := s.outputs[job.ID]
 			s.mu.RUnlock()
 
 			if data != "" {
-				s.logger.Printf("[SECONDARY_WEBHOOK] Processing saved output: %s", data)
+				logger.Printf("[SECONDARY_WEBHOOK] Processing saved output: %s", data)
 
 				// Extract variables using jq selectors if configured
-				var variables map[string]interface{}
 				if len(job.Secondary.JQSelectors) > 0 {
-					s.logger.Printf("[JQ_EXTRACTION] Extracting variables using jq selectors")
-					vars, err := s.extractVariables(data, job.Secondary.JQSelectors)
+					logger.Printf("[JQ_EXTRACTION] Extracting variables using jq selectors")
+					extractionStart := time.Now()
+					vars, err := s.extractVariables(ctx, data, job.Secondary.JQSelectors)
+					run.timing(func(t *Timing) { t.ExtractionMs = milliseconds(time.Since(extractionStart)) })
 					if err != nil {
-						s.logger.Printf("[JQ_ERROR] Failed to extract variables: %v", err)
+						logger.Printf("[JQ_ERROR] Failed to extract variables: %v", err)
 					} else {
-						variables = vars
-						s.logger.Printf("[JQ_SUCCESS] Extracted %d variables", len(variables))
+						mergeVariables(scope, vars)
+						logger.Printf("[JQ_SUCCESS] Extracted %d variables", len(vars))
 						// Log extracted variables
-						for k, v := range variables {
-							s.logger.Printf("[JQ_VARIABLE] %s = %v", k, v)
+						for k, v := range vars {
+							logger.Printf("[JQ_VARIABLE] %s = %v", k, v)
 						}
 					}
 				}
@@ -399,115 +876,283 @@ func (s *Scheduler) executeJob(job config.CronJob) {
 
 				// If template is provided, process it with extracted variables
 				if secondary.BodyTemplate != "" {
-					s.logger.Printf("[TEMPLATE_PROCESSING] Processing template: %s", secondary.BodyTemplate)
-					processedBody, err := s.processTemplate(secondary.BodyTemplate, variables)
+					logger.Printf("[TEMPLATE_PROCESSING] Processing template: %s", secondary.BodyTemplate)
+					processedBody, err := s.renderTemplate(ctx, secondary, secondary.BodyTemplate, scope)
 					if err != nil {
-						s.logger.Printf("[TEMPLATE_ERROR] Failed to process template: %v", err)
+						logger.Printf("[TEMPLATE_ERROR] Failed to process template: %v", err)
 						secondary.Body = data // Fallback to raw data
 					} else {
 						secondary.Body = processedBody
-						s.logger.Printf("[TEMPLATE_SUCCESS] Processed template result: %s", processedBody)
+						logger.Printf("[TEMPLATE_SUCCESS] Processed template result: %s", processedBody)
 					}
 				} else {
 					// No template, use raw data as before
 					secondary.Body = data
-					s.logger.Printf("[SECONDARY_WEBHOOK] Using raw saved output as body")
+					logger.Printf("[SECONDARY_WEBHOOK] Using raw saved output as body")
 				}
 
 				// Log the body that will be sent
 				if secondary.Body != "" {
-					s.logger.Printf("[SECONDARY_WEBHOOK_BODY] Sending body: %s", secondary.Body)
+					logger.Printf("[SECONDARY_WEBHOOK_BODY] Sending body: %s", secondary.Body)
 				}
 
-				s.logger.Printf("[SECONDARY_WEBHOOK] Sending %s request to %s", secondary.Method, secondary.URL)
-				if _, err := s.executeWebhook(ctx, secondary); err != nil {
-					s.logger.Printf("[SECONDARY_WEBHOOK_ERROR] Failed to execute secondary webhook for job %s: %v", job.ID, err)
-				} else {
-					s.logger.Printf("[SECONDARY_WEBHOOK_SUCCESS] Secondary webhook executed successfully for job %s", job.ID)
+				if !s.skipForEmptyVariables(ctx, job.ID, secondary, scope) {
+					logger.Printf("[SECONDARY_WEBHOOK] Sending %s request to %s", secondary.Method, secondary.URL)
+					secondaryStart := time.Now()
+					_, err := s.executeWebhook(withStep(ctx, job.ID, "secondary"), s.renderRequest(ctx, secondary, scope))
+					run.timing(func(t *Timing) { t.SecondaryMs = milliseconds(time.Since(secondaryStart)) })
+					if err != nil {
+						run.fail(err)
+						logger.Printf("[SECONDARY_WEBHOOK_ERROR] Failed to execute secondary webhook for job %s: %v", job.ID, err)
+					} else {
+						logger.Printf("[SECONDARY_WEBHOOK_SUCCESS] Secondary webhook executed successfully for job %s", job.ID)
+					}
 				}
 			} else {
-				s.logger.Printf("[SECONDARY_WEBHOOK_SKIPPED] No saved output available for job %s", job.ID)
+				logger.Printf("[SECONDARY_WEBHOOK_SKIPPED] No saved output available for job %s", job.ID)
 			}
 		} else {
-			// Execute secondary webhook without saved output
-			s.logger.Printf("[SECONDARY_WEBHOOK] Sending %s request to %s", job.Secondary.Method, job.Secondary.URL)
-
-			// Log the body that will be sent
-			if job.Secondary.Body != "" {
-				s.logger.Printf("[SECONDARY_WEBHOOK_BODY] Sending body: %s", job.Secondary.Body)
+			// Execute secondary webhook without saved output, rendering its
+			// template with the variables gathered so far
+			secondary := *job.Secondary
+			if secondary.BodyTemplate != "" {
+				processedBody, err := s.renderTemplate(ctx, secondary, secondary.BodyTemplate, scope)
+				if err != nil {
+					logger.Printf("[TEMPLATE_ERROR] Failed to process template: %v", err)
+				} else {
+					secondary.Body = processedBody
+				}
 			}
 
-			if _, err := s.executeWebhook(ctx, *job.Secondary); err != nil {
-				s.logger.Printf("[SECONDARY_WEBHOOK_ERROR] Failed to execute secondary webhook for job %s: %v", job.ID, err)
-			} else {
-				s.logger.Printf("[SECONDARY_WEBHOOK_SUCCESS] Secondary webhook executed successfully for job %s", job.ID)
+			if !s.skipForEmptyVariables(ctx, job.ID, secondary, scope) {
+				logger.Printf("[SECONDARY_WEBHOOK] Sending %s request to %s", secondary.Method, secondary.URL)
+
+				// Log the body that will be sent
+				if secondary.Body != "" {
+					logger.Printf("[SECONDARY_WEBHOOK_BODY] Sending body: %s", secondary.Body)
+				}
+
+				secondaryStart := time.Now()
+				_, err := s.executeWebhook(withStep(ctx, job.ID, "secondary"), s.renderRequest(ctx, secondary, scope))
+				run.timing(func(t *Timing) { t.SecondaryMs = milliseconds(time.Since(secondaryStart)) })
+				if err != nil {
+					run.fail(err)
+					logger.Printf("[SECONDARY_WEBHOOK_ERROR] Failed to execute secondary webhook for job %s: %v", job.ID, err)
+				} else {
+					logger.Printf("[SECONDARY_WEBHOOK_SUCCESS] Secondary webhook executed successfully for job %s", job.ID)
+				}
 			}
 		}
 	} else {
-		s.logger.Printf("[SECONDARY_WEBHOOK_NONE] No secondary webhook configured for job %s", job.ID)
+		logger.Printf("[SECONDARY_WEBHOOK_NONE] No secondary webhook configured for job %s", job.ID)
 	}
 
-	s.logger.Printf("[JOB_COMPLETE] Finished executing job: %s (ID: %s)", job.Name, job.ID)
+	// Only test runs dump the final scope, for debugging a job's templates;
+	// scheduled runs would log every variable on every tick
+	if trigger == TriggerManual {
+		logger.Printf("[JOB_SCOPE] Final variable scope for job %s: %+v", job.ID, scope)
+	}
+	logger.Printf("[JOB_COMPLETE] Finished executing job: %s (ID: %s)", job.Name, job.ID)
+	return
+}
+
+// executeFailureWebhook sends the job's OnPrimaryFailure webhook with the
+// primary's error available to templates as {{error}}, and the status code
+// and body of an error response as {{error.status}} and {{error.body}}
+func (s *Scheduler) executeFailureWebhook(ctx context.Context, job config.CronJob, primaryErr error, scope map[string]interface{}) {
+	logger := s.logFor(ctx)
+	if runFrom(ctx).wasReplaced() {
+		logger.Printf("[FAILURE_WEBHOOK_SKIPPED] Run of job %s was replaced by a newer tick, not sending the failure webhook", job.ID)
+		return
+	}
+	if !job.OnPrimaryFailure.Enabled {
+		logger.Printf("[FAILURE_WEBHOOK_DISABLED] Failure webhook is disabled for job %s", job.ID)
+		return
+	}
+
+	failure := *job.OnPrimaryFailure
+	scope["error"] = primaryErr.Error()
+	scope["error.status"] = 0
+	scope["error.body"] = ""
+	var statusErr *statusError
+	if errors.As(primaryErr, &statusErr) {
+		scope["error.status"] = statusErr.StatusCode
+		scope["error.body"] = statusErr.Body
+	}
+
+	switch {
+	case failure.BodyTemplate != "":
+		processedBody, err := s.renderTemplate(ctx, failure, failure.BodyTemplate, scope)
+		if err != nil {
+			logger.Printf("[FAILURE_WEBHOOK_TEMPLATE_ERROR] Failed to process template for job %s: %v", job.ID, err)
+		} else {
+			failure.Body = processedBody
+		}
+	case failure.Body != "":
+		processedBody, err := s.renderTemplate(ctx, failure, failure.Body, scope)
+		if err != nil {
+			logger.Printf("[FAILURE_WEBHOOK_BODY_ERROR] Failed to process body for job %s: %v", job.ID, err)
+		} else {
+			failure.Body = processedBody
+		}
+	default:
+		body, _ := json.Marshal(map[string]string{
+			"job":   job.ID,
+			"error": primaryErr.Error(),
+		})
+		failure.Body = string(body)
+	}
+
+	logger.Printf("[FAILURE_WEBHOOK] Sending %s request to %s for job %s", failure.Method, failure.URL, job.ID)
+	if _, err := s.executeWebhook(withStep(ctx, job.ID, "on_primary_failure"), s.renderRequest(ctx, failure, scope)); err != nil {
+		logger.Printf("[FAILURE_WEBHOOK_ERROR] Failed to execute failure webhook for job %s: %v", job.ID, err)
+	} else {
+		logger.Printf("[FAILURE_WEBHOOK_SUCCESS] Failure webhook executed successfully for job %s", job.ID)
+	}
+}
+
+// runPrecheck calls the job's precheck webhook and evaluates its ExpectJQ
+// condition. It returns whether the job should proceed and any variables
+// extracted from the precheck response by its JQSelectors.
+func (s *Scheduler) runPrecheck(ctx context.Context, job config.CronJob) (bool, map[string]interface{}) {
+	logger := s.logFor(ctx)
+	logger.Printf("[JOB_PRECHECK] Sending %s request to %s", job.Precheck.Method, job.Precheck.URL)
+
+	response, err := s.executeWebhook(withStep(ctx, job.ID, "precheck"), s.renderRequest(ctx, *job.Precheck, nil))
+	if err != nil {
+		logger.Printf("[JOB_PRECHECK_ERROR] Precheck failed for job %s, skipping: %v", job.ID, err)
+		runFrom(ctx).fail(err)
+		return false, nil
+	}
+
+	if job.Precheck.ExpectJQ != "" {
+		ok, err := evaluateCondition(response, job.Precheck.ExpectJQ)
+		if err != nil {
+			logger.Printf("[JOB_PRECHECK_ERROR] Failed to evaluate precheck condition for job %s, skipping: %v", job.ID, err)
+			runFrom(ctx).fail(err)
+			return false, nil
+		}
+		if !ok {
+			logger.Printf("[JOB_PRECHECK_SKIP] Precheck condition '%s' not met for job %s", job.Precheck.ExpectJQ, job.ID)
+			runFrom(ctx).skip("precheck condition not met")
+			return false, nil
+		}
+	}
+
+	logger.Printf("[JOB_PRECHECK_PASS] Precheck passed for job %s", job.ID)
+
+	if len(job.Precheck.JQSelectors) == 0 {
+		return true, nil
+	}
+
+	vars, err := s.extractVariables(ctx, response, job.Precheck.JQSelectors)
+	if err != nil {
+		logger.Printf("[JOB_PRECHECK_JQ_ERROR] Failed to extract precheck variables for job %s: %v", job.ID, err)
+		return true, nil
+	}
+
+	return true, vars
 }
 
 // extractVariables uses jq selectors to extract data from JSON response
-func (s *Scheduler) extractVariables(jsonData string, selectors map[string]string) (map[string]interface{}, error) {
-	s.logger.Printf("[EXTRACT_VARIABLES_DEBUG] Called with jsonData length: %d", len(jsonData))
-	s.logger.Printf("[EXTRACT_VARIABLES_DEBUG] Selectors: %+v", selectors)
-	s.logger.Printf("[EXTRACT_VARIABLES_DEBUG] Number of selectors: %d", len(selectors))
+func (s *Scheduler) extractVariables(ctx context.Context, jsonData string, selectors map[string]string) (map[string]interface{}, error) {
+	logger := s.logFor(ctx)
+	logger.Printf("[EXTRACT_VARIABLES_DEBUG] Called with jsonData length: %d", len(jsonData))
+	logger.Printf("[EXTRACT_VARIABLES_DEBUG] Selectors: %+v", selectors)
+	logger.Printf("[EXTRACT_VARIABLES_DEBUG] Number of selectors: %d", len(selectors))
 
 	if len(selectors) == 0 {
-		s.logger.Printf("[EXTRACT_VARIABLES_DEBUG] No selectors provided, returning nil")
+		logger.Printf("[EXTRACT_VARIABLES_DEBUG] No selectors provided, returning nil")
 		return nil, nil
 	}
 
-	// Parse the JSON data
+	// Parse the JSON data, keeping numbers as json.Number so large integers
+	// such as IDs aren't rounded through float64
+	decoder := json.NewDecoder(strings.NewReader(jsonData))
+	decoder.UseNumber()
 	var data interface{}
-	if err := json.Unmarshal([]byte(jsonData), &data); err != nil {
-		s.logger.Printf("[EXTRACT_VARIABLES_ERROR] Failed to parse JSON response: %v", err)
-		s.logger.Printf("[EXTRACT_VARIABLES_ERROR] JSON data: %s", jsonData)
+	if err := decoder.Decode(&data); err != nil {
+		logger.Printf("[EXTRACT_VARIABLES_ERROR] Failed to parse JSON response: %v", err)
+		logger.Printf("[EXTRACT_VARIABLES_ERROR] JSON data: %s", jsonData)
 		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
 	}
 
 	variables := make(map[string]interface{})
 
 	for varName, selector := range selectors {
-		s.logger.Printf("[EXTRACT_VARIABLES_DEBUG] Processing selector: %s -> %s", varName, selector)
+		logger.Printf("[EXTRACT_VARIABLES_DEBUG] Processing selector: %s -> %s", varName, selector)
 		query, err := gojq.Parse(selector)
 		if err != nil {
-			s.logger.Printf("[JQ_ERROR] Failed to parse jq selector '%s' for variable '%s': %v", selector, varName, err)
+			logger.Printf("[JQ_ERROR] Failed to parse jq selector '%s' for variable '%s': %v", selector, varName, err)
 			continue
 		}
 
-		iter := query.Run(data)
-		for {
+		// Bound both the time a selector may run and how many of its
+		// results are looked at, so pathological expressions can't hang
+		jqCtx, cancel := context.WithTimeout(ctx, jqTimeout)
+		iter := query.RunWithContext(jqCtx, data)
+		for consumed := 1; ; consumed++ {
+			if consumed > maxJQResults {
+				logger.Printf("[JQ_LIMIT] Selector '%s' for variable '%s' produced no usable value in %d results, giving up", selector, varName, maxJQResults)
+				break
+			}
 			v, ok := iter.Next()
 			if !ok {
-				s.logger.Printf("[JQ_DEBUG] No more results for selector '%s' -> '%s'", varName, selector)
+				logger.Printf("[JQ_DEBUG] No more results for selector '%s' -> '%s'", varName, selector)
 				break
 			}
 			if err, ok := v.(error); ok {
-				s.logger.Printf("[JQ_ERROR] Failed to execute jq selector '%s' for variable '%s': %v", selector, varName, err)
+				// Once the selector's deadline passes every further result
+				// is the same context error
+				if jqCtx.Err() != nil {
+					logger.Printf("[JQ_TIMEOUT] Selector '%s' for variable '%s' did not finish within %v", selector, varName, jqTimeout)
+					break
+				}
+				logger.Printf("[JQ_ERROR] Failed to execute jq selector '%s' for variable '%s': %v", selector, varName, err)
 				continue
 			}
 
+			v, ok = s.limitVariable(ctx, varName, v)
+			if !ok {
+				break
+			}
 			variables[varName] = v
-			s.logger.Printf("[JQ_EXTRACT] Extracted variable '%s' with value: %v", varName, v)
+			runFrom(ctx).redact(map[string]interface{}{varName: v})
+			logger.Printf("[JQ_EXTRACT] Extracted variable '%s' with value: %v", varName, v)
 			break // Take the first result
 		}
+		cancel()
 	}
 
-	s.logger.Printf("[EXTRACT_VARIABLES_DEBUG] Returning %d variables", len(variables))
+	logger.Printf("[EXTRACT_VARIABLES_DEBUG] Returning %d variables", len(variables))
 	return variables, nil
 }
 
 // processTemplate processes a template string with variables
-func (s *Scheduler) processTemplate(templateStr string, variables map[string]interface{}) (string, error) {
+func (s *Scheduler) processTemplate(ctx context.Context, templateStr string, variables map[string]interface{}) (string, error) {
+	logger := s.logFor(ctx)
 	if templateStr == "" {
 		return templateStr, nil
 	}
 
-	result := templateStr
+	result, err := renderConditionals(templateStr, variables)
+	if err != nil {
+		return "", err
+	}
+
+	// Placeholders with a default, {{name:-default}}, use the default text
+	// when the variable is missing or empty
+	result = placeholderPattern.ReplaceAllStringFunc(result, func(placeholder string) string {
+		tag := placeholderPattern.FindStringSubmatch(placeholder)[1]
+		if _, _, hasDefault := splitPlaceholder(tag); !hasDefault {
+			return placeholder
+		}
+		value, isDefault := resolvePlaceholder(tag, variables)
+		if isDefault {
+			logger.Printf("[TEMPLATE_DEFAULT] Replaced '%s' with its default", placeholder)
+		}
+		return templateText(value)
+	})
 
 	// Handle REMINDER variable specially if not in variables map
 	reminderPlaceholder := "{{REMINDER}}"
@@ -521,23 +1166,23 @@ func (s *Scheduler) processTemplate(templateStr string, variables map[string]int
 				escapedStr = strings.ReplaceAll(escapedStr, "\t", "\\t")
 				escapedStr = strings.ReplaceAll(escapedStr, "\"", "\\\"")
 				result = strings.ReplaceAll(result, reminderPlaceholder, escapedStr)
-				s.logger.Printf("[TEMPLATE_REPLACE] Replaced '{{REMINDER}}' with escaped string")
+				logger.Printf("[TEMPLATE_REPLACE] Replaced '{{REMINDER}}' with escaped string")
 			} else {
 				// For non-string values, marshal to JSON
 				valueBytes, err := json.Marshal(reminderText)
 				if err != nil {
-					s.logger.Printf("[TEMPLATE_ERROR] Failed to marshal REMINDER value: %v", err)
+					logger.Printf("[TEMPLATE_ERROR] Failed to marshal REMINDER value: %v", err)
 					valueStr := fmt.Sprintf("%v", reminderText)
 					result = strings.ReplaceAll(result, reminderPlaceholder, valueStr)
 				} else {
 					result = strings.ReplaceAll(result, reminderPlaceholder, string(valueBytes))
 				}
-				s.logger.Printf("[TEMPLATE_REPLACE] Replaced '{{REMINDER}}' with '%s'", string(valueBytes))
+				logger.Printf("[TEMPLATE_REPLACE] Replaced '{{REMINDER}}' with '%s'", string(valueBytes))
 			}
 		} else {
 			// If REMINDER variable is not provided, replace with empty string
 			result = strings.ReplaceAll(result, reminderPlaceholder, "")
-			s.logger.Printf("[TEMPLATE_REPLACE] Replaced '{{REMINDER}}' with empty string (no reminder provided)")
+			logger.Printf("[TEMPLATE_REPLACE] Replaced '{{REMINDER}}' with empty string (no reminder provided)")
 		}
 	}
 
@@ -561,18 +1206,22 @@ func (s *Scheduler) processTemplate(templateStr string, variables map[string]int
 			escapedStr = strings.ReplaceAll(escapedStr, "\t", "\\t")
 			escapedStr = strings.ReplaceAll(escapedStr, "\"", "\\\"")
 			result = strings.ReplaceAll(result, placeholder, escapedStr)
-			s.logger.Printf("[TEMPLATE_REPLACE] Replaced '%s' with escaped string", placeholder)
+			logger.Printf("[TEMPLATE_REPLACE] Replaced '%s' with escaped string", placeholder)
+		} else if number, ok := value.(json.Number); ok {
+			// Numbers are written exactly as they appeared in the response
+			result = strings.ReplaceAll(result, placeholder, number.String())
+			logger.Printf("[TEMPLATE_REPLACE] Replaced '%s' with '%s'", placeholder, number)
 		} else {
 			// For non-string values, marshal to JSON
 			valueBytes, err := json.Marshal(value)
 			if err != nil {
-				s.logger.Printf("[TEMPLATE_ERROR] Failed to marshal value for variable '%s': %v", varName, err)
+				logger.Printf("[TEMPLATE_ERROR] Failed to marshal value for variable '%s': %v", varName, err)
 				valueStr := fmt.Sprintf("%v", value)
 				result = strings.ReplaceAll(result, placeholder, valueStr)
 			} else {
 				result = strings.ReplaceAll(result, placeholder, string(valueBytes))
 			}
-			s.logger.Printf("[TEMPLATE_REPLACE] Replaced '%s' with '%s'", placeholder, string(valueBytes))
+			logger.Printf("[TEMPLATE_REPLACE] Replaced '%s' with '%s'", placeholder, string(valueBytes))
 		}
 	}
 
@@ -580,38 +1229,100 @@ func (s *Scheduler) processTemplate(templateStr string, variables map[string]int
 }
 
 func (s *Scheduler) executeWebhook(ctx context.Context, webhook config.WebhookConfig) (string, error) {
-	var body io.Reader
-	if webhook.Body != "" {
-		body = bytes.NewBufferString(webhook.Body)
-		s.logger.Printf("[WEBHOOK_REQUEST] Body: %s", webhook.Body)
+	logger := s.logFor(ctx)
+	ctx, webhook, err := s.prepareWebhook(ctx, webhook)
+	if err != nil {
+		return "", err
 	}
 
-	// Create a context with timeout if specified
-	requestCtx := ctx
-	if webhook.Timeout > 0 {
-		var cancel context.CancelFunc
-		requestCtx, cancel = context.WithTimeout(ctx, time.Duration(webhook.Timeout)*time.Second)
-		defer cancel()
-		s.logger.Printf("[WEBHOOK_TIMEOUT] Using custom timeout: %d seconds", webhook.Timeout)
-	} else {
-		s.logger.Printf("[WEBHOOK_TIMEOUT] Using default timeout")
+	for retry := 0; ; retry++ {
+		response, err := s.sendWebhook(ctx, webhook)
+		if err == nil && !isHead(webhook.Method) {
+			err = s.checkRetryIf(ctx, webhook, response)
+		}
+		if err == nil {
+			s.retryBudget.recordSuccess()
+			return response, nil
+		}
+
+		if failure := dnsFailure(err); failure != "" {
+			logger.Printf("[WEBHOOK_DNS_ERROR] Resolving %s: %s", webhook.URL, failure)
+		}
+
+		if retry >= webhook.Retries || !isRetryable(err, webhook.RetryOnDNS) {
+			return "", err
+		}
+
+		if !s.retryBudget.tryRetry() {
+			logger.Printf("[WEBHOOK_RETRY_SHED] Retry budget exhausted, not retrying %s", webhook.URL)
+			return "", err
+		}
+
+		delay := retryDelay(time.Duration(webhook.RetryBackoff)*time.Millisecond, retry+1, webhook.RetryJitter)
+		logger.Printf("[WEBHOOK_RETRY] Attempt %d of %d failed, retrying in %v: %v", retry+1, webhook.Retries+1, delay, err)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return "", err
+		}
+	}
+}
+
+// prepareWebhook resolves what a webhook call needs before anything is
+// sent: the round-robin target, the host policy check and secret files. The
+// returned context carries which values are secret, for log masking.
+func (s *Scheduler) prepareWebhook(ctx context.Context, webhook config.WebhookConfig) (context.Context, config.WebhookConfig, error) {
+	logger := s.logFor(ctx)
+	// Pick the target when the webhook rotates over several URLs
+	if len(webhook.URLs) > 0 {
+		webhook.URL = s.roundRobin.next(stepFrom(ctx), webhook)
+		logger.Printf("[WEBHOOK_ROUND_ROBIN] Selected %s from %d URLs", webhook.URL, len(webhook.URLs))
+	}
+
+	// Refuse denied targets before anything is sent
+	if err := s.hostPolicy.checkURL(webhook.URL); err != nil {
+		logger.Printf("[WEBHOOK_DENIED] %v", err)
+		return ctx, webhook, err
+	}
+
+	// Load secret file references fresh for every call
+	webhook, secrets, err := resolveSecrets(webhook)
+	if err != nil {
+		logger.Printf("[WEBHOOK_SECRET_ERROR] %v", err)
+		return ctx, webhook, fmt.Errorf("failed to resolve secret: %w", err)
 	}
+	return withSecrets(ctx, secrets), webhook, nil
+}
+
+// isHead reports whether a webhook method is HEAD, whose responses have no
+// body: success rests on the status code alone, and there is nothing to
+// save or extract variables from
+func isHead(method string) bool {
+	return strings.EqualFold(method, http.MethodHead)
+}
 
-	req, err := http.NewRequestWithContext(requestCtx, webhook.Method, webhook.URL, body)
+// newWebhookRequest builds the request for a webhook with its headers,
+// User-Agent, X-Request-ID and default Content-Type set
+func (s *Scheduler) newWebhookRequest(ctx context.Context, webhook config.WebhookConfig, body io.Reader) (*http.Request, error) {
+	logger := s.logFor(ctx)
+	secrets := secretsFrom(ctx)
+
+	req, err := http.NewRequestWithContext(traceRequest(ctx), webhook.Method, webhook.URL, body)
 	if err != nil {
-		s.logger.Printf("[WEBHOOK_ERROR] Failed to create request: %v", err)
-		return "", fmt.Errorf("failed to create request: %w", err)
+		logger.Printf("[WEBHOOK_ERROR] Failed to create request: %v", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Log headers
 	if len(webhook.Headers) > 0 {
-		s.logger.Printf("[WEBHOOK_HEADERS] %d headers set", len(webhook.Headers))
+		logger.Printf("[WEBHOOK_HEADERS] %d headers set", len(webhook.Headers))
 		for key, value := range webhook.Headers {
-			// Don't log sensitive headers like Authorization
-			if key != "Authorization" {
-				s.logger.Printf("[WEBHOOK_HEADER] %s: %s", key, value)
+			// Don't log sensitive headers like Authorization or secrets
+			if key != "Authorization" && !secrets.headers[key] {
+				logger.Printf("[WEBHOOK_HEADER] %s: %s", key, value)
 			} else {
-				s.logger.Printf("[WEBHOOK_HEADER] %s: ***", key)
+				logger.Printf("[WEBHOOK_HEADER] %s: ***", key)
 			}
 		}
 	}
@@ -621,60 +1332,193 @@ func (s *Scheduler) executeWebhook(ctx context.Context, webhook config.WebhookCo
 		req.Header.Set(key, value)
 	}
 
-	// Set default content type if not specified
+	// Identify ourselves and the execution to downstream services
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", version.UserAgent())
+	}
+	if runID := runIDFrom(ctx); runID != "" && req.Header.Get("X-Request-ID") == "" {
+		req.Header.Set("X-Request-ID", runID)
+	}
+
+	// Set default content type if not specified, unless detection is off
 	if req.Header.Get("Content-Type") == "" && webhook.Body != "" {
-		req.Header.Set("Content-Type", "application/json")
-		s.logger.Printf("[WEBHOOK_HEADER] Set default Content-Type: application/json")
+		contentType := webhook.ContentType
+		if contentType == "" && s.detectsContentType() {
+			contentType = detectContentType(webhook.Body)
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+			logger.Printf("[WEBHOOK_HEADER] Set default Content-Type: %s", contentType)
+		}
 	}
 
-	s.logger.Printf("[WEBHOOK_EXECUTING] %s %s", webhook.Method, webhook.URL)
-	resp, err := s.httpClient.Do(req)
+	return req, nil
+}
+
+// doWebhookRequest sends a webhook's request with the client for its
+// connection settings, classifying transport errors
+func (s *Scheduler) doWebhookRequest(ctx context.Context, webhook config.WebhookConfig, req *http.Request) (*http.Response, error) {
+	logger := s.logFor(ctx)
+	logger.Printf("[WEBHOOK_EXECUTING] %s %s", webhook.Method, webhook.URL)
+	resp, err := s.clientFor(webhook).Do(req)
 	if err != nil {
-		s.logger.Printf("[WEBHOOK_ERROR] Failed to execute webhook: %v", err)
-		return "", fmt.Errorf("failed to execute webhook: %w", err)
+		logger.Printf("[WEBHOOK_ERROR] Failed to execute webhook: %v", err)
+		if webhook.HTTP2 {
+			return nil, classifyTransportError(fmt.Errorf("failed to execute HTTP/2 webhook (target may not support HTTP/2 prior knowledge): %w", err))
+		}
+		return nil, classifyTransportError(fmt.Errorf("failed to execute webhook: %w", err))
+	}
+	return resp, nil
+}
+
+// sendWebhook performs a single webhook request and returns the response body
+func (s *Scheduler) sendWebhook(ctx context.Context, webhook config.WebhookConfig) (string, error) {
+	logger := s.logFor(ctx)
+	secrets := secretsFrom(ctx)
+
+	// A body on a GET or HEAD is usually a copy-paste mistake that some
+	// servers reject, so it is dropped unless explicitly allowed
+	if webhook.Body != "" && !webhook.AllowGetBody && (strings.EqualFold(webhook.Method, http.MethodGet) || strings.EqualFold(webhook.Method, http.MethodHead)) {
+		logger.Printf("[WEBHOOK_BODY_DROPPED] Not sending the body configured for %s %s; set allow_get_body to send it", webhook.Method, webhook.URL)
+		webhook.Body = ""
+	}
+
+	var body io.Reader
+	if webhook.Body != "" {
+		body = bytes.NewBufferString(webhook.Body)
+		if secrets.body {
+			logger.Printf("[WEBHOOK_REQUEST] Body: ***")
+		} else {
+			logger.Printf("[WEBHOOK_REQUEST] Body: %s", webhook.Body)
+		}
+	}
+
+	// Create a context with timeout if specified
+	requestCtx := ctx
+	if webhook.Timeout > 0 {
+		var cancel context.CancelFunc
+		requestCtx, cancel = context.WithTimeout(ctx, time.Duration(webhook.Timeout)*time.Second)
+		defer cancel()
+		logger.Printf("[WEBHOOK_TIMEOUT] Using custom timeout: %d seconds", webhook.Timeout)
+	} else {
+		logger.Printf("[WEBHOOK_TIMEOUT] Using default timeout")
+	}
+	if webhook.ConnectTimeout > 0 {
+		logger.Printf("[WEBHOOK_TIMEOUT] Using connect timeout: %d seconds", webhook.ConnectTimeout)
+	}
+
+	req, err := s.newWebhookRequest(requestCtx, webhook, body)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.doWebhookRequest(ctx, webhook, req)
+	if err != nil {
+		return "", err
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			s.logger.Printf("[WEBHOOK_ERROR] Failed to close response body: %v", err)
+			logger.Printf("[WEBHOOK_ERROR] Failed to close response body: %v", err)
 		}
 	}()
 
-	s.logger.Printf("[WEBHOOK_RESPONSE] Status: %d %s", resp.StatusCode, resp.Status)
+	logger.Printf("[WEBHOOK_RESPONSE] Status: %d %s", resp.StatusCode, resp.Status)
+
+	if isHead(webhook.Method) {
+		if resp.StatusCode >= 400 {
+			logger.Printf("[WEBHOOK_ERROR] Webhook returned error status %d", resp.StatusCode)
+			return "", newStatusError(resp.StatusCode, "")
+		}
+		logger.Printf("[WEBHOOK_SUCCESS] HEAD request answered with status %d", resp.StatusCode)
+		return "", nil
+	}
 
 	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		s.logger.Printf("[WEBHOOK_ERROR] Failed to read response body: %v", err)
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		logger.Printf("[WEBHOOK_ERROR] Failed to read response body: %v", err)
+		return "", classifyTransportError(fmt.Errorf("failed to read response body: %w", err))
+	}
+
+	responseBody, err = decodeResponse(webhook.ResponseCharset, responseBody)
+	if err != nil {
+		logger.Printf("[WEBHOOK_ERROR] Failed to decode response body: %v", err)
+		return "", err
 	}
 
 	if resp.StatusCode >= 400 {
-		s.logger.Printf("[WEBHOOK_ERROR] Webhook returned error status %d: %s", resp.StatusCode, string(responseBody))
-		return "", fmt.Errorf("webhook returned error status %d: %s", resp.StatusCode, string(responseBody))
+		logger.Printf("[WEBHOOK_ERROR] Webhook returned error status %d: %s", resp.StatusCode, string(responseBody))
+		return "", newStatusError(resp.StatusCode, string(responseBody))
 	}
 
-	s.logger.Printf("[WEBHOOK_SUCCESS] Response body: %s", string(responseBody))
+	logger.Printf("[WEBHOOK_SUCCESS] Response body: %s", string(responseBody))
 	return string(responseBody), nil
 }
 
+// checkRetryIf evaluates a webhook's RetryIfJQ against a successful JSON
+// response and returns a retryable error when it holds. Non-JSON responses
+// and evaluation errors never trigger a retry.
+func (s *Scheduler) checkRetryIf(ctx context.Context, webhook config.WebhookConfig, response string) error {
+	logger := s.logFor(ctx)
+	if webhook.RetryIfJQ == "" || !json.Valid([]byte(response)) {
+		return nil
+	}
+
+	retry, err := evaluateCondition(response, webhook.RetryIfJQ)
+	if err != nil {
+		logger.Printf("[WEBHOOK_RETRY_IF_ERROR] Failed to evaluate retry_if_jq: %v", err)
+		return nil
+	}
+	if !retry {
+		return nil
+	}
+
+	logger.Printf("[WEBHOOK_RETRY_IF] Response matched retry_if_jq '%s'", webhook.RetryIfJQ)
+	return &retryResponseError{Expression: webhook.RetryIfJQ, Body: response}
+}
+
+// detectContentType guesses a Content-Type for a request body. Valid JSON is
+// labelled as JSON, markup as XML, and anything else as plain text.
+func detectContentType(body string) string {
+	trimmed := strings.TrimSpace(body)
+	switch {
+	case json.Valid([]byte(trimmed)):
+		return "application/json"
+	case strings.HasPrefix(trimmed, "<"):
+		return "application/xml"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
 func (s *Scheduler) TestJob(jobID string) error {
 	job, err := s.config.GetJob(jobID)
 	if err != nil {
 		return err
 	}
 
+	release, err := s.acquireManualRun()
+	if err != nil {
+		return err
+	}
+
 	// Execute job immediately in a goroutine
-	go s.executeJob(*job)
+	go func() {
+		defer release()
+		s.executeJob(*job, TriggerManual, nil)
+	}()
 	return nil
 }
 
+// LoadJobs schedules the jobs in the configuration. Called again, it
+// reconciles the scheduled jobs with the configuration; see reload.
 func (s *Scheduler) LoadJobs() error {
-	jobs := s.config.GetAllJobs()
-	
-	for _, job := range jobs {
-		if err := s.AddJob(job); err != nil {
-			fmt.Printf("Failed to load job %s: %v\n", job.ID, err)
-		}
+	s.mu.RLock()
+	warmupConcurrency := s.warmupConcurrency
+	s.mu.RUnlock()
+	if warmupConcurrency > 0 {
+		s.warmup(s.config.GetAllJobs(), warmupConcurrency)
 	}
 
+	s.reload()
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"cron-microservice/internal/config"
+)
+
+func TestLargeIntegerRoundTrip(t *testing.T) {
+	s, _ := newTestScheduler(t)
+	ctx := context.Background()
+	structured := config.WebhookConfig{TemplateMode: TemplateModeJSON}
+
+	// Neither is exactly representable as a float64, and the second
+	// doesn't fit an int64 either
+	for _, id := range []string{"12345678901234567", "123456789012345678901"} {
+		vars, err := s.extractVariables(ctx, `{"order": {"id": `+id+`}}`, map[string]string{"id": ".order.id"})
+		if err != nil {
+			t.Fatalf("%s: extractVariables: %v", id, err)
+		}
+
+		body, err := s.processTemplate(ctx, `{"id": {{id}}, "ref": "order-{{id}}"}`, vars)
+		if err != nil {
+			t.Fatalf("%s: processTemplate: %v", id, err)
+		}
+		if want := `{"id": ` + id + `, "ref": "order-` + id + `"}`; body != want {
+			t.Errorf("string template = %s, want %s", body, want)
+		}
+
+		body, err = s.renderTemplate(ctx, structured, `{"id": "{{id}}"}`, vars)
+		if err != nil {
+			t.Fatalf("%s: renderTemplate: %v", id, err)
+		}
+		if want := `{"id":` + id + `}`; body != want {
+			t.Errorf("json template = %s, want %s", body, want)
+		}
+	}
+}
+
+func TestJSONNumberInTemplate(t *testing.T) {
+	s, _ := newTestScheduler(t)
+
+	// Saved outputs and typed defaults are decoded with UseNumber
+	vars := map[string]interface{}{"id": json.Number("12345678901234567")}
+	body, err := s.processTemplate(context.Background(), `{"id": {{id}}}`, vars)
+	if err != nil {
+		t.Fatalf("processTemplate: %v", err)
+	}
+	if want := `{"id": 12345678901234567}`; body != want {
+		t.Errorf("body = %s, want %s", body, want)
+	}
+}
@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+// mergeVariables copies vars into scope, overriding any existing entries
+// with the same name. The scope accumulates variables across the steps of
+// a single execution so later steps can reference anything produced
+// earlier.
+func mergeVariables(scope, vars map[string]interface{}) {
+	for name, value := range vars {
+		scope[name] = value
+	}
+}
+
+// timeVariables returns the built-in {{now.*}} variables for a run started
+// at now, in the scheduler's time zone
+func timeVariables(now time.Time) map[string]interface{} {
+	now = now.In(config.DefaultLocation())
+	return map[string]interface{}{
+		"now.date":    now.Format(time.DateOnly),
+		"now.time":    now.Format(time.TimeOnly),
+		"now.rfc3339": now.Format(time.RFC3339),
+		"now.unix":    now.Unix(),
+		"now.unix_ms": now.UnixMilli(),
+		"now.weekday": now.Weekday().String(),
+	}
+}
+
+// emptyVariables returns, sorted, the names of the selector variables that
+// are missing from scope or hold an empty value
+func emptyVariables(selectors map[string]string, scope map[string]interface{}) []string {
+	empty := []string{}
+	for name := range selectors {
+		if isEmptyValue(scope[name]) {
+			empty = append(empty, name)
+		}
+	}
+	sort.Strings(empty)
+	return empty
+}
+
+// isEmptyValue reports whether an extracted value counts as empty: nil,
+// an empty string, or an empty array or object. false and 0 are values.
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// skipForEmptyVariables reports whether a secondary webhook with
+// OnlyIfVarsNonEmpty set must be skipped because at least one of its jq
+// selectors produced no value or an empty one
+func (s *Scheduler) skipForEmptyVariables(ctx context.Context, jobID string, webhook config.WebhookConfig, scope map[string]interface{}) bool {
+	logger := s.logFor(ctx)
+	if !webhook.OnlyIfVarsNonEmpty || len(webhook.JQSelectors) == 0 {
+		return false
+	}
+
+	empty := emptyVariables(webhook.JQSelectors, scope)
+	if len(empty) == 0 {
+		return false
+	}
+
+	logger.Printf("[SECONDARY_SKIP_EMPTY_VARS] Skipping secondary webhook for job %s, empty variables: %s", jobID, strings.Join(empty, ", "))
+	return true
+}
@@ -0,0 +1,114 @@
+package scheduler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"cron-microservice/internal/config"
+)
+
+func TestIsEmptyValue(t *testing.T) {
+	for _, tc := range []struct {
+		value interface{}
+		empty bool
+	}{
+		{nil, true},
+		{"", true},
+		{[]interface{}{}, true},
+		{map[string]interface{}{}, true},
+		{"x", false},
+		{0, false},
+		{false, false},
+		{[]interface{}{nil}, false},
+		{map[string]interface{}{"a": nil}, false},
+	} {
+		if got := isEmptyValue(tc.value); got != tc.empty {
+			t.Errorf("isEmptyValue(%#v) = %v, want %v", tc.value, got, tc.empty)
+		}
+	}
+}
+
+func TestSkipForEmptyVariables(t *testing.T) {
+	selectors := map[string]string{"id": ".id", "tags": ".tags", "count": ".count"}
+	for _, tc := range []struct {
+		name      string
+		onlyIf    bool
+		selectors map[string]string
+		scope     map[string]interface{}
+		skip      bool
+		logged    string
+	}{
+		{"all set", true, selectors, map[string]interface{}{"id": "a", "tags": []interface{}{"x"}, "count": 0}, false, ""},
+		{"one empty", true, selectors, map[string]interface{}{"id": "", "tags": []interface{}{"x"}, "count": 0}, true, "empty variables: id"},
+		{"several missing", true, selectors, map[string]interface{}{"count": 0}, true, "empty variables: id, tags"},
+		{"flag off", false, selectors, nil, false, ""},
+		{"no selectors", true, nil, nil, false, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, logs := newTestScheduler(t)
+			webhook := config.WebhookConfig{OnlyIfVarsNonEmpty: tc.onlyIf, JQSelectors: tc.selectors}
+
+			if got := s.skipForEmptyVariables(context.Background(), "job", webhook, tc.scope); got != tc.skip {
+				t.Errorf("skip = %v, want %v", got, tc.skip)
+			}
+			if tc.logged != "" && !strings.Contains(logs.String(), tc.logged) {
+				t.Errorf("log does not name %q:\n%s", tc.logged, logs.String())
+			}
+			if !tc.skip && strings.Contains(logs.String(), "[SECONDARY_SKIP_EMPTY_VARS]") {
+				t.Errorf("skip logged for a secondary that runs:\n%s", logs.String())
+			}
+		})
+	}
+}
+
+func TestSecondarySkippedForEmptyVariables(t *testing.T) {
+	for _, tc := range []struct {
+		response string
+		calls    int64
+	}{
+		{`{"id": "a"}`, 1},
+		{`{"id": ""}`, 0},
+		{`{}`, 0},
+	} {
+		t.Run(tc.response, func(t *testing.T) {
+			primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tc.response))
+			}))
+			defer primary.Close()
+
+			var calls atomic.Int64
+			secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+			}))
+			defer secondary.Close()
+
+			job := config.CronJob{
+				ID:         "job",
+				Name:       "job",
+				Enabled:    true,
+				SaveOutput: true,
+				Primary:    config.WebhookConfig{URL: primary.URL, Method: http.MethodGet, Enabled: true},
+				Secondary: &config.WebhookConfig{
+					URL:                secondary.URL,
+					Method:             http.MethodPost,
+					BodyTemplate:       `{"id": "{{id}}"}`,
+					JQSelectors:        map[string]string{"id": ".id"},
+					OnlyIfVarsNonEmpty: true,
+					Enabled:            true,
+				},
+			}
+			s, logs := newTestScheduler(t, job)
+
+			if r := s.executeJob(job, TriggerManual, nil); r.record.Status != StatusSuccess {
+				t.Fatalf("run status = %s (%s), want success", r.record.Status, r.record.Error)
+			}
+			if got := calls.Load(); got != tc.calls {
+				t.Errorf("secondary called %d times, want %d\n%s", got, tc.calls, logs.String())
+			}
+		})
+	}
+}
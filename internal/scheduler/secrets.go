@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"cron-microservice/internal/config"
+)
+
+// secretFilePrefix marks a header or body value to be read from a file,
+// e.g. "file:/run/secrets/token"
+const secretFilePrefix = "file:"
+
+type secretsKey struct{}
+
+// secretFields records which parts of a request hold resolved secrets, so
+// logging can mask them
+type secretFields struct {
+	headers map[string]bool
+	body    bool
+}
+
+// isSecretRef reports whether a value is a secret file reference. Only
+// absolute paths are recognized so ordinary text starting with "file:" is
+// left alone.
+func isSecretRef(value string) bool {
+	return strings.HasPrefix(value, secretFilePrefix+"/")
+}
+
+// readSecret loads a secret file, dropping trailing newlines. Errors name
+// the path but never the contents.
+func readSecret(ref string) (string, error) {
+	path := strings.TrimPrefix(ref, secretFilePrefix)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file %s: %w", path, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// resolveSecrets replaces header and body values that reference secret
+// files with the files' current contents. Files are read on every call so
+// rotated secrets are picked up.
+func resolveSecrets(webhook config.WebhookConfig) (config.WebhookConfig, secretFields, error) {
+	fields := secretFields{headers: map[string]bool{}}
+
+	if isSecretRef(webhook.Body) {
+		body, err := readSecret(webhook.Body)
+		if err != nil {
+			return webhook, fields, err
+		}
+		webhook.Body = body
+		fields.body = true
+	}
+
+	headers := make(map[string]string, len(webhook.Headers))
+	for key, value := range webhook.Headers {
+		if isSecretRef(value) {
+			secret, err := readSecret(value)
+			if err != nil {
+				return webhook, fields, fmt.Errorf("header %s: %w", key, err)
+			}
+			value = secret
+			fields.headers[key] = true
+		}
+		headers[key] = value
+	}
+	webhook.Headers = headers
+
+	return webhook, fields, nil
+}
+
+// withSecrets attaches the secret fields of a request to ctx
+func withSecrets(ctx context.Context, fields secretFields) context.Context {
+	return context.WithValue(ctx, secretsKey{}, fields)
+}
+
+// secretsFrom returns the secret fields carried by ctx
+func secretsFrom(ctx context.Context) secretFields {
+	fields, _ := ctx.Value(secretsKey{}).(secretFields)
+	return fields
+}
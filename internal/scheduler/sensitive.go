@@ -0,0 +1,106 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// redactedValue replaces sensitive values in log lines
+const redactedValue = "***"
+
+// templateEscaper escapes a string the way processTemplate substitutes it
+var templateEscaper = strings.NewReplacer("\n", "\\n", "\r", "\\r", "\t", "\\t", "\"", "\\\"")
+
+// isSensitive reports whether a variable is one of the job's
+// sensitive_variables, or the {{prev.<name>}} copy of one
+func isSensitive(names []string, variable string) bool {
+	variable = strings.TrimPrefix(variable, "prev.")
+	for _, name := range names {
+		if name == variable {
+			return true
+		}
+	}
+	return false
+}
+
+// redact registers the values of the run's sensitive variables found in
+// vars, so the run's log lines mask them from then on. Each value is also
+// registered escaped the ways it appears in rendered bodies, query strings
+// and logged responses echoing a JSON request.
+func (r *run) redact(vars map[string]interface{}) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.sensitive) == 0 {
+		return
+	}
+
+	for name, value := range vars {
+		if !isSensitive(r.sensitive, name) {
+			continue
+		}
+		text := stringifyValue(value)
+		if text == "" {
+			continue
+		}
+
+		escaped := jsonEscape(text)
+		forms := []string{text, escaped, jsonEscape(escaped), templateEscaper.Replace(text), url.QueryEscape(text), url.PathEscape(text)}
+		for _, form := range forms {
+			if !containsString(r.secrets, form) {
+				r.secrets = append(r.secrets, form)
+			}
+		}
+	}
+
+	// Longest first, so a value containing another is masked whole
+	sort.SliceStable(r.secrets, func(i, j int) bool { return len(r.secrets[i]) > len(r.secrets[j]) })
+}
+
+// jsonEscape returns text as it appears inside a JSON string
+func jsonEscape(text string) string {
+	quoted, _ := json.Marshal(text)
+	return string(quoted[1 : len(quoted)-1])
+}
+
+// mask replaces every registered sensitive value in line
+func (r *run) mask(line string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.maskLocked(line)
+}
+
+// maskLocked is mask for callers already holding r.mu
+func (r *run) maskLocked(line string) string {
+	for _, secret := range r.secrets {
+		line = strings.ReplaceAll(line, secret, redactedValue)
+	}
+	return line
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// sensitiveNames returns each job's sensitive_variables by job ID
+func (s *Scheduler) sensitiveNames() map[string][]string {
+	names := make(map[string][]string)
+	for _, job := range s.config.GetAllJobs() {
+		if len(job.SensitiveVariables) > 0 {
+			names[job.ID] = job.SensitiveVariables
+		}
+	}
+	return names
+}
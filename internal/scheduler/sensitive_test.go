@@ -0,0 +1,156 @@
+package scheduler
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"cron-microservice/internal/config"
+)
+
+const testSecret = "s3cr3t-t0ken"
+
+// syncBuffer is a bytes.Buffer safe to log to from timers and runs while
+// a test reads it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// newTestScheduler returns a scheduler with the given jobs in its config,
+// saved to a temporary directory, logging to the returned buffer
+func newTestScheduler(t *testing.T, jobs ...config.CronJob) (*Scheduler, *syncBuffer) {
+	t.Helper()
+	cfg := config.New(filepath.Join(t.TempDir(), "config.yaml"))
+	for _, job := range jobs {
+		if err := cfg.AddJob(job); err != nil {
+			t.Fatalf("AddJob(%s): %v", job.ID, err)
+		}
+	}
+
+	s := New(cfg)
+	logs := &syncBuffer{}
+	s.logger = log.New(logs, "", 0)
+	return s, logs
+}
+
+// echoServer answers every request with its body and the given status
+func echoServer(t *testing.T, status int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(status)
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSensitiveVariablesMaskedInLogs(t *testing.T) {
+	server := echoServer(t, http.StatusOK)
+	job := config.CronJob{
+		ID:                 "secret",
+		Name:               "secret",
+		Enabled:            true,
+		SensitiveVariables: []string{"token"},
+		Primary: config.WebhookConfig{
+			URL:          server.URL,
+			Method:       http.MethodPost,
+			BodyTemplate: `{"token": "{{token}}"}`,
+			Enabled:      true,
+		},
+	}
+	s, logs := newTestScheduler(t, job)
+
+	result, err := s.Invoke(job.ID, map[string]interface{}{"token": testSecret})
+	if err != nil {
+		t.Fatalf("Invoke: %v", err)
+	}
+	if result.Execution.Status != StatusSuccess {
+		t.Fatalf("status = %s (%s), want success", result.Execution.Status, result.Execution.Error)
+	}
+	if !strings.Contains(result.Response, testSecret) {
+		t.Fatalf("the webhook did not receive the secret: %q", result.Response)
+	}
+	if strings.Contains(logs.String(), testSecret) {
+		t.Errorf("secret found in logs:\n%s", logs.String())
+	}
+	if !strings.Contains(logs.String(), redactedValue) {
+		t.Errorf("no masked value in logs:\n%s", logs.String())
+	}
+}
+
+func TestSensitiveVariablesMaskedInErrors(t *testing.T) {
+	server := echoServer(t, http.StatusInternalServerError)
+	job := config.CronJob{
+		ID:                 "secret",
+		Name:               "secret",
+		Enabled:            true,
+		SensitiveVariables: []string{"token"},
+		Primary: config.WebhookConfig{
+			URL:          server.URL,
+			Method:       http.MethodPost,
+			BodyTemplate: `{"token": "{{token}}"}`,
+			Enabled:      true,
+		},
+	}
+	s, logs := newTestScheduler(t, job)
+
+	result, err := s.Invoke(job.ID, map[string]interface{}{"token": testSecret})
+	if err != nil {
+		t.Fatalf("Invoke: %v", err)
+	}
+	if result.Execution.Status != StatusFailure {
+		t.Fatalf("status = %s, want failure", result.Execution.Status)
+	}
+	if strings.Contains(result.Execution.Error, testSecret) {
+		t.Errorf("secret found in the execution error: %s", result.Execution.Error)
+	}
+	for _, execution := range s.History(job.ID) {
+		if strings.Contains(execution.Error, testSecret) {
+			t.Errorf("secret found in the history: %s", execution.Error)
+		}
+	}
+	if strings.Contains(logs.String(), testSecret) {
+		t.Errorf("secret found in logs:\n%s", logs.String())
+	}
+}
+
+func TestSensitiveVariablesMaskedInStatus(t *testing.T) {
+	job := config.CronJob{
+		ID:                 "cursor",
+		Name:               "cursor",
+		SensitiveVariables: []string{"token"},
+		Secondary: &config.WebhookConfig{
+			JQSelectors: map[string]string{"token": ".token", "cursor": ".cursor"},
+		},
+	}
+	s, _ := newTestScheduler(t, job)
+	s.previousVariables.values[job.ID] = map[string]interface{}{"token": testSecret, "cursor": "42"}
+
+	previous := s.Status().PreviousVariables[job.ID]
+	if previous["token"] != redactedValue {
+		t.Errorf("token = %v, want %s", previous["token"], redactedValue)
+	}
+	if previous["cursor"] != "42" {
+		t.Errorf("cursor = %v, want 42", previous["cursor"])
+	}
+}
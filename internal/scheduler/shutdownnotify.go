@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// shutdownNotice is the default body of the OnShutdown webhook
+type shutdownNotice struct {
+	Event         string  `json:"event"`
+	Hostname      string  `json:"hostname"`
+	Reason        string  `json:"reason"`
+	UptimeSeconds float64 `json:"uptime_seconds"`
+	InFlight      int64   `json:"in_flight"`
+}
+
+// NotifyShutdown sends the config's OnShutdown webhook, giving up after
+// timeout so a slow endpoint can't hold up shutdown. Without a body or
+// template the notice itself is sent as JSON; templates can use
+// {{hostname}}, {{reason}}, {{uptime}}, {{uptime_seconds}} and {{in_flight}}.
+func (s *Scheduler) NotifyShutdown(reason string, timeout time.Duration) {
+	webhook := s.config.OnShutdown
+	if webhook == nil || !webhook.Enabled {
+		return
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	uptime := time.Since(s.startedAt).Round(time.Second)
+	notice := shutdownNotice{
+		Event:         "shutdown",
+		Hostname:      hostname,
+		Reason:        reason,
+		UptimeSeconds: uptime.Seconds(),
+		InFlight:      s.counters.inFlight.Load(),
+	}
+
+	body, err := json.Marshal(notice)
+	if err != nil {
+		s.logger.Printf("[SHUTDOWN_NOTIFY_ERROR] Failed to marshal shutdown notice: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(withStep(context.Background(), "scheduler", "on_shutdown"), timeout)
+	defer cancel()
+
+	notification := *webhook
+	variables := map[string]interface{}{
+		"hostname":       notice.Hostname,
+		"reason":         notice.Reason,
+		"uptime":         uptime.String(),
+		"uptime_seconds": notice.UptimeSeconds,
+		"in_flight":      notice.InFlight,
+	}
+
+	switch {
+	case notification.BodyTemplate != "":
+		rendered, err := s.renderTemplate(ctx, notification, notification.BodyTemplate, variables)
+		if err != nil {
+			s.logger.Printf("[SHUTDOWN_NOTIFY_ERROR] Failed to process template: %v", err)
+			return
+		}
+		notification.Body = rendered
+	case notification.Body == "":
+		notification.Body = string(body)
+	}
+
+	if _, err := s.executeWebhook(ctx, s.renderRequest(ctx, notification, variables)); err != nil {
+		s.logger.Printf("[SHUTDOWN_NOTIFY_ERROR] Failed to send shutdown notification: %v", err)
+		return
+	}
+
+	s.logger.Printf("[SHUTDOWN_NOTIFY_SENT] Notified shutdown: %s", body)
+}
@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"cron-microservice/internal/config"
+)
+
+// seedOutputs puts the latest saved output of each job in the job's
+// OutputsFrom in scope as {{outputs.<id>}}. JSON outputs are decoded so
+// templates and computed_vars can reach into them; anything else is kept as
+// text. Outputs are whatever each job saved last, however long ago, and a
+// job that has saved nothing since startup gives an empty value.
+func (s *Scheduler) seedOutputs(ctx context.Context, job config.CronJob, scope map[string]interface{}) {
+	if len(job.OutputsFrom) == 0 {
+		return
+	}
+	logger := s.logFor(ctx)
+
+	s.mu.RLock()
+	outputs := make(map[string]string, len(job.OutputsFrom))
+	for _, id := range job.OutputsFrom {
+		if output, ok := s.outputs[id]; ok {
+			outputs[id] = output
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, id := range job.OutputsFrom {
+		output, ok := outputs[id]
+		if !ok {
+			logger.Printf("[OUTPUTS_MISSING] Job %s has no saved output, {{outputs.%s}} is empty", id, id)
+			scope["outputs."+id] = ""
+			continue
+		}
+		scope["outputs."+id] = decodeOutput(output)
+	}
+}
+
+// decodeOutput returns a saved output as a JSON value when it is one
+// document, keeping numbers exact, or else as text
+func decodeOutput(output string) interface{} {
+	decoder := json.NewDecoder(strings.NewReader(output))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return output
+	}
+	return value
+}
+
+// OutputsFromWarnings reports jobs in the job's OutputsFrom that are not
+// among jobs or don't set save_output, whose {{outputs.<id>}} would always
+// be empty
+func OutputsFromWarnings(job config.CronJob, jobs []config.CronJob) []string {
+	saves := make(map[string]bool, len(jobs))
+	for _, other := range jobs {
+		saves[other.ID] = other.SaveOutput
+	}
+
+	warnings := []string{}
+	for _, id := range job.OutputsFrom {
+		if id == "" || id == job.ID {
+			continue // Validation errors of their own
+		}
+		saveOutput, exists := saves[id]
+		switch {
+		case !exists:
+			warnings = append(warnings, fmt.Sprintf("outputs_from: job %s does not exist, so {{outputs.%s}} is always empty", id, id))
+		case !saveOutput:
+			warnings = append(warnings, fmt.Sprintf("outputs_from: job %s does not set save_output, so {{outputs.%s}} is always empty", id, id))
+		}
+	}
+	return warnings
+}
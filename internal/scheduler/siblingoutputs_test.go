@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"cron-microservice/internal/config"
+)
+
+func TestOutputsFromWarnings(t *testing.T) {
+	jobs := []config.CronJob{
+		{ID: "saver", SaveOutput: true},
+		{ID: "plain"},
+		{ID: "report", OutputsFrom: []string{"saver", "plain", "ghost"}},
+	}
+
+	warnings := OutputsFromWarnings(jobs[2], jobs)
+	if len(warnings) != 2 {
+		t.Fatalf("warnings = %q, want two", warnings)
+	}
+	if !strings.Contains(warnings[0], "plain does not set save_output") {
+		t.Errorf("warning for a job without save_output = %q", warnings[0])
+	}
+	if !strings.Contains(warnings[1], "ghost does not exist") {
+		t.Errorf("warning for a missing job = %q", warnings[1])
+	}
+
+	report := ValidateJobs(jobs, nil)
+	if got := report.Jobs[2].Warnings; !containsPrefix(got, "outputs_from: job plain") || !containsPrefix(got, "outputs_from: job ghost") {
+		t.Errorf("ValidateJobs warnings = %q, want both outputs_from warnings", got)
+	}
+}
+
+func TestSeedOutputsMissingAndStale(t *testing.T) {
+	s, logs := newTestScheduler(t)
+	// Saved by an earlier run, however long ago
+	s.outputs["saver"] = `{"count": 12345678901234567}`
+
+	job := config.CronJob{ID: "report", OutputsFrom: []string{"saver", "ghost"}}
+	scope := map[string]interface{}{}
+	s.seedOutputs(context.Background(), job, scope)
+
+	saved, ok := scope["outputs.saver"].(map[string]interface{})
+	if !ok || saved["count"] != json.Number("12345678901234567") {
+		t.Errorf("outputs.saver = %#v, want the decoded saved output", scope["outputs.saver"])
+	}
+	if missing, ok := scope["outputs.ghost"]; !ok || missing != "" {
+		t.Errorf("outputs.ghost = %#v, want an empty value", missing)
+	}
+	if !strings.Contains(logs.String(), "[OUTPUTS_MISSING] Job ghost") {
+		t.Errorf("missing output not logged:\n%s", logs.String())
+	}
+}
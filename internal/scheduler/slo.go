@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"time"
+)
+
+// defaultSLOWindow is how far back SLO data looks when no window is set
+const defaultSLOWindow = time.Hour
+
+// SLO is a job's success ratio over a rolling window, computed from its
+// execution history
+type SLO struct {
+	JobID        string         `json:"job_id"`
+	Window       string         `json:"window"`
+	Runs         int            `json:"runs"` // Runs started within the window, skipped ones included
+	Successes    int            `json:"successes"`
+	Failures     int            `json:"failures"`
+	FailureKinds map[string]int `json:"failure_kinds"` // Failures by error kind, every kind included
+	Skipped      int            `json:"skipped"`
+	SuccessRatio *float64       `json:"success_ratio"`            // Of non-skipped runs; null when there were none
+	Uptime       *float64       `json:"uptime_percent,omitempty"` // Success ratio as a percentage, for monitor jobs
+	Truncated    bool           `json:"truncated"`                // The kept history doesn't reach back to the window start
+}
+
+// SetSLOWindow sets the default window for SLO data. Zero or less restores
+// the default of one hour.
+func (s *Scheduler) SetSLOWindow(window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sloWindow = window
+}
+
+// SLOWindow returns the default window for SLO data
+func (s *Scheduler) SLOWindow() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.sloWindow <= 0 {
+		return defaultSLOWindow
+	}
+	return s.sloWindow
+}
+
+// SLO computes a job's success ratio over the runs started within window
+// before now. Only the last defaultHistorySize runs are kept, so a busy job
+// may report a truncated window.
+func (s *Scheduler) SLO(jobID string, window time.Duration) SLO {
+	since := time.Now().Add(-window)
+	slo := SLO{JobID: jobID, Window: window.String(), FailureKinds: map[string]int{}}
+	for _, kind := range ErrorKinds {
+		slo.FailureKinds[kind] = 0
+	}
+
+	// The history is in finish order, so a long run that started before the
+	// window may be followed by runs that started within it
+	executions := s.history.forJob(jobID)
+	var oldest time.Time
+	for _, e := range executions {
+		if oldest.IsZero() || e.StartedAt.Before(oldest) {
+			oldest = e.StartedAt
+		}
+		if e.StartedAt.Before(since) {
+			continue
+		}
+		slo.Runs++
+		switch e.Status {
+		case StatusSuccess:
+			slo.Successes++
+		case StatusFailure:
+			slo.Failures++
+			kind := e.ErrorKind
+			if kind == "" {
+				kind = ErrorKindOther
+			}
+			slo.FailureKinds[kind]++
+		case StatusSkipped:
+			slo.Skipped++
+		}
+	}
+
+	// A full history whose oldest run started inside the window may have
+	// dropped older runs that did too
+	if len(executions) >= s.history.limit && !oldest.Before(since) {
+		slo.Truncated = true
+	}
+
+	if decided := slo.Successes + slo.Failures; decided > 0 {
+		ratio := float64(slo.Successes) / float64(decided)
+		slo.SuccessRatio = &ratio
+		if job, err := s.config.GetJob(jobID); err == nil && job.Monitor {
+			uptime := ratio * 100
+			slo.Uptime = &uptime
+		}
+	}
+	return slo
+}
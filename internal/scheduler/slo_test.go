@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+func TestSLOFailureKinds(t *testing.T) {
+	server := echoServer(t, http.StatusInternalServerError)
+	job := config.CronJob{
+		ID:      "job",
+		Name:    "job",
+		Enabled: true,
+		Primary: config.WebhookConfig{URL: server.URL, Method: http.MethodPost, Enabled: true},
+	}
+	s, _ := newTestScheduler(t, job)
+
+	s.executeJob(job, TriggerManual, nil)
+	s.executeJob(job, TriggerManual, nil)
+
+	slo := s.SLO(job.ID, time.Hour)
+	if slo.Failures != 2 {
+		t.Fatalf("failures = %d, want 2", slo.Failures)
+	}
+	for _, kind := range ErrorKinds {
+		want := 0
+		if kind == ErrorKindStatus {
+			want = 2
+		}
+		if got, ok := slo.FailureKinds[kind]; !ok || got != want {
+			t.Errorf("failure_kinds[%s] = %d (present: %v), want %d", kind, got, ok, want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package scheduler
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// LoadJobsAfterSplay waits a random delay within window before loading
+// jobs, so a fleet of instances started together doesn't schedule and warm
+// up in lockstep. It gives up without loading anything if the scheduler is
+// stopped during the delay.
+func (s *Scheduler) LoadJobsAfterSplay(window time.Duration) error {
+	if window > 0 {
+		delay := rand.N(window)
+		s.logger.Printf("[STARTUP_SPLAY] Delaying job loading by %v", delay.Round(time.Millisecond))
+
+		select {
+		case <-time.After(delay):
+		case <-s.stopSweeper:
+			s.logger.Printf("[STARTUP_SPLAY] Stopped during startup splay, not loading jobs")
+			return nil
+		}
+	}
+
+	return s.LoadJobs()
+}
@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"sync/atomic"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+// executionCounters tracks executions since the process started
+type executionCounters struct {
+	inFlight  atomic.Int64
+	total     atomic.Int64
+	successes atomic.Int64
+	failures  atomic.Int64
+	skipped   atomic.Int64
+}
+
+// Stats summarizes jobs and executions across the whole scheduler
+type Stats struct {
+	Jobs            int            `json:"jobs"`
+	MaxJobs         int            `json:"max_jobs"` // Job limit, 0 when there is none
+	JobsByState     map[string]int `json:"jobs_by_state"`
+	ScheduledJobs   int            `json:"scheduled_jobs"`
+	ActiveReminders int            `json:"active_reminders"`
+	InFlight        int64          `json:"in_flight"`
+	Executions      int64          `json:"executions"`
+	Successes       int64          `json:"successes"`
+	Failures        int64          `json:"failures"`
+	Skipped         int64          `json:"skipped"`
+	SuccessRate     float64        `json:"success_rate"` // Of finished, non-skipped executions
+	FailureRate     float64        `json:"failure_rate"`
+	UptimeSeconds   float64        `json:"uptime_seconds"`
+}
+
+// Stats returns job counts from the config alongside execution counters
+// since the process started
+func (s *Scheduler) Stats() Stats {
+	jobs := s.config.GetAllJobs()
+	byState := map[string]int{
+		config.StateEnabled:  0,
+		config.StateDisabled: 0,
+		config.StateDraft:    0,
+		config.StateTemplate: 0,
+	}
+	for _, job := range jobs {
+		byState[job.State()]++
+	}
+
+	s.mu.RLock()
+	scheduled := len(s.jobs)
+	reminders := len(s.reminders)
+	s.mu.RUnlock()
+
+	stats := Stats{
+		Jobs:            len(jobs),
+		MaxJobs:         s.config.MaxJobs(),
+		JobsByState:     byState,
+		ScheduledJobs:   scheduled,
+		ActiveReminders: reminders,
+		InFlight:        s.counters.inFlight.Load(),
+		Executions:      s.counters.total.Load(),
+		Successes:       s.counters.successes.Load(),
+		Failures:        s.counters.failures.Load(),
+		Skipped:         s.counters.skipped.Load(),
+		UptimeSeconds:   time.Since(s.startedAt).Round(time.Second).Seconds(),
+	}
+
+	if decided := stats.Successes + stats.Failures; decided > 0 {
+		stats.SuccessRate = float64(stats.Successes) / float64(decided)
+		stats.FailureRate = float64(stats.Failures) / float64(decided)
+	}
+
+	return stats
+}
+
+// countFinished adds a finished execution to the counters
+func (c *executionCounters) countFinished(status string) {
+	c.inFlight.Add(-1)
+	c.total.Add(1)
+	switch status {
+	case StatusSuccess:
+		c.successes.Add(1)
+	case StatusFailure:
+		c.failures.Add(1)
+	case StatusSkipped:
+		c.skipped.Add(1)
+	}
+}
@@ -0,0 +1,152 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+// requestContext bounds a single webhook request by the webhook's timeout,
+// if it sets one
+func requestContext(ctx context.Context, webhook config.WebhookConfig) (context.Context, context.CancelFunc) {
+	if webhook.Timeout > 0 {
+		return context.WithTimeout(ctx, time.Duration(webhook.Timeout)*time.Second)
+	}
+	return context.WithCancel(ctx)
+}
+
+// executeStreamed runs a StreamToSecondary job's webhooks, piping the
+// primary's response body into the secondary's request body as it arrives
+// instead of reading it into memory first. The primary is the already
+// rendered request. Nothing is extracted, templated into the secondary body
+// or saved, and neither call is retried, since the body can only be read
+// once.
+func (s *Scheduler) executeStreamed(ctx context.Context, job config.CronJob, primary config.WebhookConfig, scope map[string]interface{}) {
+	logger := s.logFor(ctx)
+	run := runFrom(ctx)
+
+	primaryStart := time.Now()
+	resp, cancelPrimary, err := s.openStream(withStep(ctx, job.ID, "primary"), primary)
+	run.timing(func(t *Timing) { t.PrimaryMs = milliseconds(time.Since(primaryStart)) })
+	if err != nil {
+		run.fail(err)
+		logger.Printf("[PRIMARY_WEBHOOK_ERROR] Failed to execute primary webhook for job %s: %v", job.ID, err)
+		if job.OnPrimaryFailure != nil {
+			s.executeFailureWebhook(ctx, job, err, scope)
+		}
+		return
+	}
+	defer cancelPrimary()
+	defer resp.Body.Close()
+
+	secondaryCtx, secondary, err := s.prepareWebhook(withStep(ctx, job.ID, "secondary"), s.renderRequest(ctx, *job.Secondary, scope))
+	if err != nil {
+		run.fail(err)
+		logger.Printf("[SECONDARY_WEBHOOK_ERROR] Failed to execute secondary webhook for job %s: %v", job.ID, err)
+		return
+	}
+	secondary.Body = ""
+
+	// Copy the primary response into the pipe while the secondary request
+	// reads from it. Closing the reader unblocks the copy if the secondary
+	// fails before consuming everything.
+	reader, writer := io.Pipe()
+	defer reader.Close()
+	streamed := make(chan int64, 1)
+	go func() {
+		n, err := io.Copy(writer, resp.Body)
+		writer.CloseWithError(err)
+		streamed <- n
+	}()
+
+	requestCtx, cancel := requestContext(secondaryCtx, secondary)
+	defer cancel()
+	req, err := s.newWebhookRequest(requestCtx, secondary, reader)
+	if err != nil {
+		run.fail(err)
+		cancelPrimary()
+		return
+	}
+	if req.Header.Get("Content-Type") == "" {
+		contentType := secondary.ContentType
+		if contentType == "" && s.detectsContentType() {
+			contentType = resp.Header.Get("Content-Type")
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+	}
+	if resp.ContentLength > 0 {
+		req.ContentLength = resp.ContentLength
+	}
+
+	logger.Printf("[SECONDARY_WEBHOOK_STREAM] Streaming the primary response to %s %s", secondary.Method, secondary.URL)
+	secondaryStart := time.Now()
+	err = s.finishStream(secondaryCtx, secondary, req)
+	run.timing(func(t *Timing) { t.SecondaryMs = milliseconds(time.Since(secondaryStart)) })
+	// Stop the copy if the secondary stopped reading early
+	reader.Close()
+	cancelPrimary()
+	n := <-streamed
+	if err != nil {
+		run.fail(err)
+		logger.Printf("[SECONDARY_WEBHOOK_ERROR] Failed to execute secondary webhook for job %s after streaming %d bytes: %v", job.ID, n, err)
+		return
+	}
+	logger.Printf("[SECONDARY_WEBHOOK_SUCCESS] Streamed %d bytes from the primary to the secondary webhook for job %s", n, job.ID)
+}
+
+// openStream sends a webhook request and returns the response with its body
+// unread, along with the function that releases the request's timeout. An
+// error status is returned as an error with the start of the body.
+func (s *Scheduler) openStream(ctx context.Context, webhook config.WebhookConfig) (*http.Response, context.CancelFunc, error) {
+	ctx, webhook, err := s.prepareWebhook(ctx, webhook)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var body io.Reader
+	if webhook.Body != "" {
+		body = strings.NewReader(webhook.Body)
+	}
+	requestCtx, cancel := requestContext(ctx, webhook)
+	req, err := s.newWebhookRequest(requestCtx, webhook, body)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	resp, err := s.doWebhookRequest(ctx, webhook, req)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	s.logFor(ctx).Printf("[WEBHOOK_RESPONSE] Status: %d %s", resp.StatusCode, resp.Status)
+	if resp.StatusCode >= 400 {
+		defer cancel()
+		defer resp.Body.Close()
+		start, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize+1))
+		return nil, nil, newStatusError(resp.StatusCode, string(start))
+	}
+	return resp, cancel, nil
+}
+
+// finishStream sends a streaming request and checks its response status
+func (s *Scheduler) finishStream(ctx context.Context, webhook config.WebhookConfig, req *http.Request) error {
+	resp, err := s.doWebhookRequest(ctx, webhook, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	s.logFor(ctx).Printf("[WEBHOOK_RESPONSE] Status: %d %s", resp.StatusCode, resp.Status)
+	start, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize+1))
+	if resp.StatusCode >= 400 {
+		return newStatusError(resp.StatusCode, string(start))
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"cron-microservice/internal/config"
+)
+
+// Template modes for WebhookConfig.TemplateMode
+const (
+	TemplateModeString = "string"
+	TemplateModeJSON   = "json"
+)
+
+// renderTemplate renders a body template for a webhook according to its
+// TemplateMode, defaulting to plain string substitution
+func (s *Scheduler) renderTemplate(ctx context.Context, webhook config.WebhookConfig, templateStr string, variables map[string]interface{}) (string, error) {
+	s.computeVariables(ctx, webhook, variables)
+	runFrom(ctx).redact(variables)
+	switch webhook.TemplateMode {
+	case "", TemplateModeString:
+		return s.processTemplate(ctx, templateStr, variables)
+	case TemplateModeJSON:
+		return renderStructured(templateStr, variables)
+	default:
+		return "", fmt.Errorf("unknown template mode %q", webhook.TemplateMode)
+	}
+}
+
+// renderStructured renders a template that is itself a JSON document. A
+// string that is exactly one placeholder, such as "{{ids}}", is replaced by
+// the variable as a real JSON value (null when missing, or the default of
+// "{{ids:-[]}}"); placeholders inside longer strings are interpolated as
+// text. Object keys are left untouched.
+func renderStructured(templateStr string, variables map[string]interface{}) (string, error) {
+	if strings.TrimSpace(templateStr) == "" {
+		return templateStr, nil
+	}
+
+	templateStr, err := renderConditionals(templateStr, variables)
+	if err != nil {
+		return "", err
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(templateStr))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return "", fmt.Errorf("failed to parse JSON template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(substituteValue(document, variables)); err != nil {
+		return "", fmt.Errorf("failed to encode rendered template: %w", err)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// substituteValue walks a decoded JSON value replacing placeholders
+func substituteValue(value interface{}, variables map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = substituteValue(item, variables)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = substituteValue(item, variables)
+		}
+		return v
+	case string:
+		if match := placeholderPattern.FindStringSubmatch(v); match != nil && match[0] == v {
+			value, isDefault := resolvePlaceholder(match[1], variables)
+			if isDefault {
+				return typedDefault(value.(string))
+			}
+			return value
+		}
+		return placeholderPattern.ReplaceAllStringFunc(v, func(placeholder string) string {
+			value, _ := resolvePlaceholder(placeholderPattern.FindStringSubmatch(placeholder)[1], variables)
+			return stringifyValue(value)
+		})
+	default:
+		return v
+	}
+}
+
+// stringifyValue formats a variable for interpolation into a string
+func stringifyValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"sort"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+// Suspension is a job whose scheduled ticks are skipped until a set time
+type Suspension struct {
+	JobID     string    `json:"job_id"`
+	Until     time.Time `json:"until"`
+	Remaining string    `json:"remaining"`
+}
+
+type suspension struct {
+	until time.Time
+	timer *time.Timer
+}
+
+// suspended reports whether a job's scheduled ticks are currently skipped
+func suspended(job config.CronJob) bool {
+	return job.SuspendedUntil != nil && time.Now().Before(*job.SuspendedUntil)
+}
+
+// trackSuspension arms a timer that clears the job's SuspendedUntil once it
+// passes, replacing any earlier timer. The caller must hold s.mu.
+func (s *Scheduler) trackSuspension(job config.CronJob) {
+	s.clearSuspension(job.ID)
+	if job.SuspendedUntil == nil {
+		return
+	}
+
+	until := *job.SuspendedUntil
+	s.suspensions[job.ID] = &suspension{
+		until: until,
+		timer: time.AfterFunc(time.Until(until), func() { s.endSuspension(job.ID, until) }),
+	}
+}
+
+// clearSuspension stops and forgets a job's suspension timer. The caller
+// must hold s.mu.
+func (s *Scheduler) clearSuspension(jobID string) {
+	if current, exists := s.suspensions[jobID]; exists {
+		current.timer.Stop()
+		delete(s.suspensions, jobID)
+	}
+}
+
+// endSuspension clears an expired SuspendedUntil from the job and persists
+// the change, unless the job was updated with another suspension meanwhile
+func (s *Scheduler) endSuspension(jobID string, until time.Time) {
+	current, err := s.config.GetJob(jobID)
+	if err != nil || current.SuspendedUntil == nil || !current.SuspendedUntil.Equal(until) {
+		return
+	}
+
+	// The suspension has ended either way; a read-only config just keeps
+	// the stale suspended_until
+	if s.config.IsReadOnly() {
+		s.mu.Lock()
+		if current, exists := s.suspensions[jobID]; exists && current.until.Equal(until) {
+			delete(s.suspensions, jobID)
+		}
+		s.mu.Unlock()
+		s.logger.Printf("[JOB_SUSPEND_END] Job %s resumed after suspension until %s; the configuration is read-only, so suspended_until stays in it", jobID, until.Format(time.RFC3339))
+		return
+	}
+
+	job := *current
+	job.SuspendedUntil = nil
+
+	if err := s.config.AddJob(job); err != nil {
+		s.logger.Printf("[JOB_SUSPEND_ERROR] Failed to update job %s: %v", jobID, err)
+		return
+	}
+	if err := s.config.Save(); err != nil {
+		s.logger.Printf("[JOB_SUSPEND_ERROR] Failed to save config for job %s: %v", jobID, err)
+	}
+	if err := s.AddJob(job); err != nil {
+		s.logger.Printf("[JOB_SUSPEND_ERROR] Failed to reschedule job %s: %v", jobID, err)
+	}
+
+	s.logger.Printf("[JOB_SUSPEND_END] Job %s resumed after suspension until %s", jobID, until.Format(time.RFC3339))
+}
+
+// activeSuspensions lists jobs that are still suspended, sorted by job ID.
+// The caller must hold s.mu.
+func (s *Scheduler) activeSuspensions() []Suspension {
+	now := time.Now()
+	suspensions := []Suspension{}
+	for jobID, current := range s.suspensions {
+		if !now.Before(current.until) {
+			continue
+		}
+		suspensions = append(suspensions, Suspension{
+			JobID:     jobID,
+			Until:     current.until,
+			Remaining: current.until.Sub(now).Round(time.Second).String(),
+		})
+	}
+	sort.Slice(suspensions, func(i, j int) bool { return suspensions[i].JobID < suspensions[j].JobID })
+	return suspensions
+}
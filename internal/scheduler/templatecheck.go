@@ -0,0 +1,219 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cron-microservice/internal/config"
+)
+
+// builtinVariables are provided by the scheduler rather than by selectors:
+// REMINDER and message in reminder runs, error, error.status and error.body
+// in failure webhooks, diff in change notifications, item in for_each
+// secondaries, the auto-disable alert variables, and the current time in
+// every run. Precheck and secondary selectors also provide prev.<name> from
+// the last run.
+var builtinVariables = map[string]bool{
+	"REMINDER": true,
+	"message":  true,
+	"error":    true,
+	"job":      true,
+	"name":     true,
+	"reason":   true,
+	"failures": true,
+	"diff":     true,
+	"item":     true,
+
+	"error.status": true,
+	"error.body":   true,
+
+	"now.date":    true,
+	"now.time":    true,
+	"now.rfc3339": true,
+	"now.unix":    true,
+	"now.unix_ms": true,
+	"now.weekday": true,
+}
+
+// Sources of a referenced variable in a VariableUse
+const (
+	SourceSelector = "jq_selector"  // Extracted by a webhook's jq_selectors
+	SourceComputed = "computed"     // Derived by a webhook's computed_vars
+	SourcePrevious = "previous_run" // prev.<name> kept from the last successful run
+	SourceOutputs  = "outputs"      // outputs.<id>, the saved output of a job in outputs_from
+	SourceBuiltin  = "builtin"      // Set by the scheduler, such as now.date
+	SourceItem     = "item"         // The element of a for_each secondary
+	SourceInvoke   = "invoke"       // Expected from the invoke request of a job template
+)
+
+// VariableReport lists the placeholders a job's templates reference and
+// where each one's value comes from
+type VariableReport struct {
+	Variables []VariableUse `json:"variables"` // Sorted by name
+	Satisfied []string      `json:"satisfied"` // Referenced variables with a source
+	Dangling  []string      `json:"dangling"`  // Referenced variables nothing provides
+	Unused    []string      `json:"unused"`    // Selector variables no template references
+}
+
+// VariableUse is one referenced variable
+type VariableUse struct {
+	Name         string   `json:"name"`
+	ReferencedBy []string `json:"referenced_by"`    // Webhooks whose templates use it, such as "secondary"
+	Source       string   `json:"source,omitempty"` // Empty when the variable is dangling
+	ProvidedBy   []string `json:"provided_by"`      // Webhooks whose selectors or computed_vars set it
+}
+
+// jobWebhooks returns a job's webhooks with the field each is configured
+// in, leaving out unset ones
+func jobWebhooks(job *config.CronJob) []namedWebhook {
+	webhooks := []namedWebhook{
+		{"primary", &job.Primary},
+		{"secondary", job.Secondary},
+		{"precheck", job.Precheck},
+		{"enabled_check", job.EnabledCheck},
+		{"on_primary_failure", job.OnPrimaryFailure},
+		{"on_auto_disable", job.OnAutoDisable},
+	}
+	for i := range job.Reminders {
+		webhooks = append(webhooks, namedWebhook{fmt.Sprintf("reminders[%d].webhook", i), job.Reminders[i].Webhook})
+	}
+
+	set := webhooks[:0]
+	for _, w := range webhooks {
+		if w.webhook != nil {
+			set = append(set, w)
+		}
+	}
+	return set
+}
+
+// AnalyzeVariables statically matches the placeholders in a job's URLs,
+// bodies, body templates, default body template, headers and query
+// parameters against its jq selectors, computed variables and the
+// variables the scheduler provides. Placeholders of job templates that
+// nothing provides are expected at invoke time rather than dangling.
+func AnalyzeVariables(job config.CronJob) VariableReport {
+	selectors := map[string][]string{}
+	computed := map[string][]string{}
+	referenced := map[string][]string{}
+	reference := func(from string, texts []string) {
+		seen := map[string]bool{}
+		for _, text := range texts {
+			for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+				if name, ok := templateReference(match[1]); ok && !seen[name] {
+					seen[name] = true
+					referenced[name] = append(referenced[name], from)
+				}
+			}
+		}
+	}
+
+	for _, w := range jobWebhooks(&job) {
+		for name := range w.webhook.JQSelectors {
+			selectors[name] = append(selectors[name], w.name)
+		}
+		for name := range w.webhook.ComputedVars {
+			computed[name] = append(computed[name], w.name)
+		}
+		reference(w.name, templateTexts(*w.webhook))
+	}
+	reference("default_body_template", []string{job.DefaultBodyTemplate})
+	previous := map[string]bool{}
+	for _, name := range selectorNames(job) {
+		previous["prev."+name] = true
+	}
+	outputs := map[string]bool{}
+	for _, id := range job.OutputsFrom {
+		outputs["outputs."+id] = true
+	}
+
+	report := VariableReport{Variables: []VariableUse{}, Satisfied: []string{}, Dangling: []string{}, Unused: []string{}}
+	for _, name := range sortedKeys(keySet(referenced)) {
+		use := VariableUse{Name: name, ReferencedBy: referenced[name], ProvidedBy: []string{}}
+		switch {
+		case selectors[name] != nil:
+			use.Source = SourceSelector
+			use.ProvidedBy = selectors[name]
+		case computed[name] != nil:
+			use.Source = SourceComputed
+			use.ProvidedBy = computed[name]
+		case previous[name]:
+			use.Source = SourcePrevious
+		case outputs[name]:
+			use.Source = SourceOutputs
+		case builtinVariables[name]:
+			use.Source = SourceBuiltin
+		case strings.HasPrefix(name, "item."):
+			use.Source = SourceItem
+		case job.Template:
+			use.Source = SourceInvoke
+		}
+
+		report.Variables = append(report.Variables, use)
+		if use.Source == "" {
+			report.Dangling = append(report.Dangling, name)
+		} else {
+			report.Satisfied = append(report.Satisfied, name)
+		}
+	}
+	for _, name := range sortedKeys(keySet(selectors)) {
+		if referenced[name] == nil && referenced["prev."+name] == nil {
+			report.Unused = append(report.Unused, name)
+		}
+	}
+
+	return report
+}
+
+// TemplateWarnings looks for likely template mistakes in a job: jq selector
+// variables that no template references, placeholders that neither a
+// selector nor the scheduler provides, and malformed conditional blocks.
+// The warnings are advisory; templates of job templates may reference
+// variables supplied at invoke time, so unknown placeholders are not
+// reported for them.
+func TemplateWarnings(job config.CronJob) []string {
+	report := AnalyzeVariables(job)
+
+	warnings := []string{}
+	for _, name := range report.Unused {
+		warnings = append(warnings, fmt.Sprintf("jq selector variable %q is never referenced by a template", name))
+	}
+	for _, name := range report.Dangling {
+		warnings = append(warnings, fmt.Sprintf("placeholder {{%s}} is not provided by any jq selector", name))
+	}
+	warnings = append(warnings, conditionalWarnings(job)...)
+
+	return warnings
+}
+
+// templateTexts returns every part of a webhook that may hold placeholders
+func templateTexts(webhook config.WebhookConfig) []string {
+	texts := []string{webhook.URL, webhook.Body, webhook.BodyTemplate}
+	texts = append(texts, webhook.URLs...)
+	for _, value := range webhook.Headers {
+		texts = append(texts, value)
+	}
+	for _, value := range webhook.QueryParams {
+		texts = append(texts, value)
+	}
+	return texts
+}
+
+// keySet returns the keys of a map as a set
+func keySet[V any](m map[string]V) map[string]bool {
+	set := make(map[string]bool, len(m))
+	for key := range m {
+		set[key] = true
+	}
+	return set
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
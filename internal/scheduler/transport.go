@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+// transportKey identifies the transport settings a webhook needs. Webhooks
+// with the default key share the scheduler's httpClient.
+type transportKey struct {
+	http2          bool
+	resolve        string        // Canonical form of WebhookConfig.ResolveTo
+	connectTimeout time.Duration // Dial and TLS handshake limit, 0 means the default
+}
+
+// clients caches HTTP clients for webhooks that need a non-default transport
+type clients struct {
+	mu    sync.Mutex
+	byKey map[transportKey]*http.Client
+}
+
+func newClients() *clients {
+	return &clients{byKey: make(map[transportKey]*http.Client)}
+}
+
+// clientFor returns the HTTP client to use for a webhook, building and
+// caching a dedicated transport when the webhook needs one
+func (s *Scheduler) clientFor(webhook config.WebhookConfig) *http.Client {
+	key := transportKey{
+		http2:          webhook.HTTP2,
+		resolve:        resolveKey(webhook.ResolveTo),
+		connectTimeout: time.Duration(webhook.ConnectTimeout) * time.Second,
+	}
+	if key == (transportKey{}) {
+		return s.httpClient
+	}
+
+	s.clients.mu.Lock()
+	defer s.clients.mu.Unlock()
+
+	if client, ok := s.clients.byKey[key]; ok {
+		return client
+	}
+
+	transport := newTransport()
+	if key.http2 {
+		// Speak HTTP/2 only: h2c with prior knowledge for cleartext
+		// targets, and h2 via ALPN for TLS targets
+		protocols := new(http.Protocols)
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+		transport.Protocols = protocols
+	}
+
+	if len(webhook.ResolveTo) > 0 || s.hostPolicy != nil || key.connectTimeout > 0 {
+		transport.DialContext = s.dialer(webhook.ResolveTo, key.connectTimeout)
+	}
+	if key.connectTimeout > 0 {
+		// Fail fast on unreachable hosts; the response may still take up
+		// to the webhook's overall timeout
+		transport.TLSHandshakeTimeout = key.connectTimeout
+	}
+
+	client := &http.Client{
+		Timeout:   s.httpClient.Timeout,
+		Transport: transport,
+	}
+	s.clients.byKey[key] = client
+	return client
+}
+
+// resolveKey renders a ResolveTo mapping in a stable order so equal
+// mappings share a transport
+func resolveKey(resolveTo map[string]string) string {
+	if len(resolveTo) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(resolveTo))
+	for from, to := range resolveTo {
+		pairs = append(pairs, from+"="+to)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// newTransport returns a copy of http.DefaultTransport to customize
+func newTransport() *http.Transport {
+	return http.DefaultTransport.(*http.Transport).Clone()
+}
+
+// dialer returns a dial function that connects to the mapped address for
+// any host:port listed in resolveTo, like curl's --resolve, and enforces
+// the host policy on the requested host together with the address it
+// dials. The request URL is unchanged, so the Host header and TLS server
+// name still use the original host. Each connection attempt is limited to
+// timeout, or 30 seconds when it is 0.
+func (s *Scheduler) dialer(resolveTo map[string]string, timeout time.Duration) func(context.Context, string, string) (net.Conn, error) {
+	mapping := make(map[string]string, len(resolveTo))
+	for from, to := range resolveTo {
+		mapping[from] = to
+	}
+
+	policy := s.hostPolicy
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		requested := addr
+		if target, ok := mapping[addr]; ok {
+			addr = target
+		}
+		if policy != nil {
+			return policy.dial(ctx, dialer, network, requested, addr)
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+}
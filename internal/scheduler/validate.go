@@ -0,0 +1,294 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/itchyny/gojq"
+	"github.com/robfig/cron/v3"
+
+	"cron-microservice/internal/config"
+)
+
+// ValidationReport is the result of validating a set of jobs without
+// applying them
+type ValidationReport struct {
+	Valid bool        `json:"valid"` // No job has errors; warnings are allowed
+	Jobs  []JobReport `json:"jobs"`
+}
+
+// JobReport lists what is wrong with one job. Errors would make the job
+// fail to load or run; warnings are likely mistakes that still load.
+type JobReport struct {
+	Index    int      `json:"index"` // Position in the submitted jobs
+	ID       string   `json:"id"`
+	Errors   []string `json:"errors"`
+	Warnings []string `json:"warnings"`
+}
+
+// webhookMethods are the HTTP methods a webhook may use
+var webhookMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+	http.MethodPatch, http.MethodDelete, http.MethodOptions,
+}
+
+// ValidateJobs checks jobs as a whole without touching the scheduler or
+// the config: schedules, webhook URLs and methods, jq expressions, template
+// settings, reminder IDs, quiet hours, IDs duplicated across jobs, and
+// after_job chains that name a job not in the set or loop back. When cfg is
+// set, reminders are also held to its lead time limit, as a save would.
+// Template placeholder problems, outputs_from jobs that save no output and
+// schedules that never fire are reported as warnings.
+func ValidateJobs(jobs []config.CronJob, cfg *config.Config) ValidationReport {
+	report := ValidationReport{Valid: true, Jobs: make([]JobReport, 0, len(jobs))}
+
+	indexes := make(map[string][]int, len(jobs))
+	for i, job := range jobs {
+		if job.ID != "" {
+			indexes[job.ID] = append(indexes[job.ID], i)
+		}
+	}
+
+	for i, job := range jobs {
+		jobReport := JobReport{Index: i, ID: job.ID, Errors: validateJob(job, jobs, cfg), Warnings: []string{}}
+
+		if job.ID == "" {
+			jobReport.Warnings = append(jobReport.Warnings, "job has no id; one is generated when it is created")
+		} else if others := indexes[job.ID]; len(others) > 1 {
+			jobReport.Errors = append(jobReport.Errors, fmt.Sprintf("duplicate job id %q at indexes %s", job.ID, joinInts(others)))
+		}
+
+		jobReport.Warnings = append(jobReport.Warnings, TemplateWarnings(job)...)
+		jobReport.Warnings = append(jobReport.Warnings, OutputsFromWarnings(job, jobs)...)
+		jobReport.Warnings = append(jobReport.Warnings, ScheduleWarnings(job)...)
+		if len(jobReport.Errors) > 0 {
+			report.Valid = false
+		}
+		report.Jobs = append(report.Jobs, jobReport)
+	}
+
+	return report
+}
+
+// namedWebhook is a webhook of a job with the field it is configured in
+type namedWebhook struct {
+	name    string
+	webhook *config.WebhookConfig
+}
+
+// CheckJob prepares a job submitted through the API for saving and returns
+// the first reason the save must be refused. It resolves an interval into
+// a schedule and gives reminders without an ID one. cfg supplies the saved
+// jobs after_job may name and the reminder lead time limit.
+func CheckJob(cfg *config.Config, job *config.CronJob) error {
+	if err := ResolveSchedule(job); err != nil {
+		return err
+	}
+
+	// Reminders without an ID get one; duplicate IDs would be ambiguous
+	job.AssignReminderIDs()
+	if problems := saveProblems(*job, cfg.GetAllJobs(), cfg); len(problems) > 0 {
+		return errors.New(problems[0])
+	}
+	return nil
+}
+
+// saveProblems returns the reasons a job can't be saved, shared by CheckJob
+// and ValidateJobs. jobs are the jobs it is saved alongside, for after_job
+// chains, and cfg, when set, supplies the reminder lead time limit.
+func saveProblems(job config.CronJob, jobs []config.CronJob, cfg *config.Config) []string {
+	errs := []string{}
+
+	if err := job.ValidateReminderIDs(); err != nil {
+		errs = append(errs, fmt.Sprintf("reminders: %v", err))
+	}
+	if cfg != nil {
+		if err := cfg.CheckReminderLeadTime(job); err != nil {
+			errs = append(errs, fmt.Sprintf("reminders: %v", err))
+		}
+	}
+	if err := job.QuietHours.Validate(); err != nil {
+		errs = append(errs, fmt.Sprintf("quiet_hours: %v", err))
+	}
+	// Any other policy would silently act as independent
+	switch job.ReminderPolicy {
+	case "", ReminderPolicyIndependent, ReminderPolicyCascade:
+	default:
+		errs = append(errs, fmt.Sprintf("reminder_policy: unknown policy %q", job.ReminderPolicy))
+	}
+	if err := job.ValidateAfterJob(); err != nil {
+		errs = append(errs, fmt.Sprintf("after_job: %v", err))
+	} else if err := job.ValidateAfterJobChain(jobs); err != nil {
+		errs = append(errs, fmt.Sprintf("after_job: %v", err))
+	}
+
+	// An unknown charset would only fail once the request had been sent
+	for _, w := range jobWebhooks(&job) {
+		if _, err := decodeResponse(w.webhook.ResponseCharset, nil); err != nil {
+			errs = append(errs, w.name+": "+err.Error())
+		}
+	}
+
+	return errs
+}
+
+// validateJob returns the errors in a single job validated among jobs
+func validateJob(job config.CronJob, jobs []config.CronJob, cfg *config.Config) []string {
+	errs := []string{}
+
+	if err := ResolveSchedule(&job); err != nil {
+		errs = append(errs, fmt.Sprintf("interval: %v", err))
+	} else if job.Schedule == "" {
+		if !job.Template && job.AfterJob == "" {
+			errs = append(errs, "schedule: a schedule, interval or after_job is required")
+		}
+	} else if _, err := cron.ParseStandard(job.Schedule); err != nil {
+		errs = append(errs, fmt.Sprintf("schedule: %v", err))
+	}
+
+	// Reminders without an ID get one on create, so only explicit IDs can clash
+	job.Reminders = append([]config.Reminder(nil), job.Reminders...)
+	job.AssignReminderIDs()
+	errs = append(errs, saveProblems(job, jobs, cfg)...)
+	for _, id := range job.OutputsFrom {
+		if id == "" {
+			errs = append(errs, "outputs_from: job ids must not be empty")
+		} else if id == job.ID {
+			errs = append(errs, fmt.Sprintf("outputs_from: job %s cannot read its own output", id))
+		}
+	}
+	if _, err := job.LatenessLimit(); err != nil {
+		errs = append(errs, fmt.Sprintf("max_lateness: %v", err))
+	}
+	if job.StreamToSecondary {
+		if job.Secondary == nil {
+			errs = append(errs, "stream_to_secondary: the job has no secondary webhook")
+		} else if job.Secondary.ForEach != "" {
+			errs = append(errs, "stream_to_secondary: can't be combined with the secondary's for_each")
+		}
+	}
+	if job.Monitor {
+		if !monitorMethod(job.Primary.Method) {
+			errs = append(errs, fmt.Sprintf("monitor: the primary must use GET or HEAD, not %s", job.Primary.Method))
+		}
+		if job.Secondary != nil {
+			errs = append(errs, "monitor: a monitor job has no secondary webhook")
+		}
+	}
+
+	for _, w := range jobWebhooks(&job) {
+		for _, problem := range validateWebhook(*w.webhook) {
+			errs = append(errs, w.name+": "+problem)
+		}
+	}
+
+	return errs
+}
+
+// validateWebhook returns the problems in a webhook's configuration
+func validateWebhook(webhook config.WebhookConfig) []string {
+	problems := []string{}
+
+	urls := webhook.URLs
+	if len(urls) == 0 {
+		urls = []string{webhook.URL}
+	}
+	for _, rawURL := range urls {
+		if err := validateURL(rawURL); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(webhook.Weights) > len(webhook.URLs) {
+		problems = append(problems, fmt.Sprintf("%d weights given for %d urls", len(webhook.Weights), len(webhook.URLs)))
+	}
+
+	if webhook.Method != "" && !containsFold(webhookMethods, webhook.Method) {
+		problems = append(problems, fmt.Sprintf("unknown method %q", webhook.Method))
+	}
+
+	names := make([]string, 0, len(webhook.JQSelectors))
+	for name := range webhook.JQSelectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := gojq.Parse(webhook.JQSelectors[name]); err != nil {
+			problems = append(problems, fmt.Sprintf("jq selector %q: %v", name, err))
+		}
+	}
+	names = names[:0]
+	for name := range webhook.ComputedVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := gojq.Parse(webhook.ComputedVars[name]); err != nil {
+			problems = append(problems, fmt.Sprintf("computed variable %q: %v", name, err))
+		}
+	}
+	for field, expression := range map[string]string{"expect_jq": webhook.ExpectJQ, "retry_if_jq": webhook.RetryIfJQ, "for_each": webhook.ForEach, "output_transform": webhook.OutputTransform} {
+		if expression == "" {
+			continue
+		}
+		if _, err := gojq.Parse(expression); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", field, err))
+		}
+	}
+
+	switch webhook.TemplateMode {
+	case "", TemplateModeString, TemplateModeJSON:
+	default:
+		problems = append(problems, fmt.Sprintf("unknown template mode %q", webhook.TemplateMode))
+	}
+	switch webhook.RetryJitter {
+	case "", JitterNone, JitterFull, JitterEqual:
+	default:
+		problems = append(problems, fmt.Sprintf("unknown retry jitter %q", webhook.RetryJitter))
+	}
+
+	sort.Strings(problems)
+	return problems
+}
+
+// validateURL checks that a webhook URL is an absolute http or https URL.
+// URLs with placeholders are checked with the placeholders blanked out.
+func validateURL(rawURL string) error {
+	if rawURL == "" {
+		return fmt.Errorf("url is required")
+	}
+
+	u, err := url.Parse(placeholderPattern.ReplaceAllString(rawURL, "x"))
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %v", rawURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("url %q must use http or https", rawURL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("url %q has no host", rawURL)
+	}
+	return nil
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinInts renders indexes as "0, 3 and 5"
+func joinInts(values []int) string {
+	items := make([]string, len(values))
+	for i, v := range values {
+		items[i] = fmt.Sprint(v)
+	}
+	return joinList(items)
+}
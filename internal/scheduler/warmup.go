@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"cron-microservice/internal/config"
+)
+
+// warmupTimeout bounds each warmup request
+const warmupTimeout = 5 * time.Second
+
+// SetWarmup enables connection warmup in LoadJobs with the given number of
+// concurrent requests. 0 disables warmup.
+func (s *Scheduler) SetWarmup(concurrency int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.warmupConcurrency = concurrency
+}
+
+// warmup issues a HEAD request to every unique host the jobs call, priming
+// DNS and the connection pool so the first real request is fast. Failures
+// are logged and otherwise ignored.
+func (s *Scheduler) warmup(jobs []config.CronJob, concurrency int) {
+	type target struct {
+		origin  string
+		webhook config.WebhookConfig
+	}
+
+	seen := make(map[string]bool)
+	targets := []target{}
+	add := func(webhook *config.WebhookConfig) {
+		if webhook == nil {
+			return
+		}
+		urls := webhook.URLs
+		if len(urls) == 0 {
+			urls = []string{webhook.URL}
+		}
+		for _, raw := range urls {
+			u, err := url.Parse(raw)
+			if err != nil || u.Host == "" {
+				continue
+			}
+			origin := u.Scheme + "://" + u.Host
+			key := origin
+			if webhook.HTTP2 {
+				key += "#h2"
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			targets = append(targets, target{origin: origin, webhook: *webhook})
+		}
+	}
+
+	for _, job := range jobs {
+		if job.Draft || job.Template {
+			continue
+		}
+		add(job.Precheck)
+		add(job.EnabledCheck)
+		add(&job.Primary)
+		add(job.Secondary)
+		add(job.OnPrimaryFailure)
+		for _, reminder := range job.Reminders {
+			add(reminder.Webhook)
+		}
+	}
+
+	if len(targets) == 0 {
+		return
+	}
+
+	s.logger.Printf("[WARMUP_START] Warming up %d hosts with concurrency %d", len(targets), concurrency)
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, t := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			ctx, cancel := context.WithTimeout(context.Background(), warmupTimeout)
+			defer cancel()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodHead, t.origin+"/", nil)
+			if err != nil {
+				return
+			}
+			resp, err := s.clientFor(t.webhook).Do(req)
+			if err != nil {
+				s.logger.Printf("[WARMUP_ERROR] Failed to warm up %s: %v", t.origin, err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	s.logger.Printf("[WARMUP_COMPLETE] Warmed up %d hosts", len(targets))
+}
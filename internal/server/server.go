@@ -2,35 +2,115 @@ package server
 
 import (
 	"embed"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"io/fs"
+	"mime"
 	"net/http"
+	"net/url"
+	"os"
 	"path"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"cron-microservice/internal/config"
 	"cron-microservice/internal/scheduler"
+	"cron-microservice/internal/version"
+
+	"gopkg.in/yaml.v3"
 )
 
 //go:embed web/static/* web/templates/*
 var webFS embed.FS
 
 type Server struct {
-	config    *config.Config
-	scheduler *scheduler.Scheduler
-	templates *template.Template
+	config      *config.Config
+	scheduler   *scheduler.Scheduler
+	templatesMu sync.RWMutex // Guards templates, which are replaced on reload
+	templates   *template.Template
+	templateDir string // Directory the UI templates are re-read from on each page load, or empty for the embedded ones
+	basePath    string // Prefix of every route, such as "/cron", or empty
 }
 
 func New(cfg *config.Config, sched *scheduler.Scheduler) *Server {
-	tmpl := template.Must(template.ParseFS(webFS, "web/templates/*.html"))
-
-	return &Server{
+	s := &Server{
 		config:    cfg,
 		scheduler: sched,
-		templates: tmpl,
 	}
+
+	templates, err := s.parseTemplates()
+	if err != nil {
+		panic(err)
+	}
+	s.templates = templates
+	return s
+}
+
+// parseTemplates parses the UI templates from the template directory when
+// one is set, otherwise from the embedded files
+func (s *Server) parseTemplates() (*template.Template, error) {
+	// Templates read the base path when rendered, so it may be set after New
+	funcs := template.FuncMap{
+		"basePath": func() string { return s.basePath },
+		"version":  func() string { return version.Get().String() },
+	}
+
+	var templateFS fs.FS = webFS
+	pattern := "web/templates/*.html"
+	if s.templateDir != "" {
+		templateFS = os.DirFS(s.templateDir)
+		pattern = "*.html"
+	}
+	templates, err := template.New("").Funcs(funcs).ParseFS(templateFS, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse templates: %w", err)
+	}
+	return templates, nil
+}
+
+// SetTemplateDir serves the UI templates from dir instead of the embedded
+// ones, re-reading them on every page load so edits show up without a
+// restart. It is meant for development and fails if dir has no valid
+// templates. Call it before Start.
+func (s *Server) SetTemplateDir(dir string) error {
+	s.templateDir = dir
+	return s.ReloadTemplates()
+}
+
+// ReloadTemplates re-parses the UI templates and swaps them in for requests
+// that start afterwards. The current templates are kept when parsing fails.
+func (s *Server) ReloadTemplates() error {
+	templates, err := s.parseTemplates()
+	if err != nil {
+		return err
+	}
+
+	s.templatesMu.Lock()
+	defer s.templatesMu.Unlock()
+
+	s.templates = templates
+	return nil
+}
+
+// currentTemplates returns the UI templates in use
+func (s *Server) currentTemplates() *template.Template {
+	s.templatesMu.RLock()
+	defer s.templatesMu.RUnlock()
+
+	return s.templates
+}
+
+// SetBasePath serves every route, UI, static files and API alike, under
+// prefix, for reverse proxies that route a path such as /cron/ to the
+// service. Call it before Start.
+func (s *Server) SetBasePath(prefix string) {
+	s.basePath = strings.TrimSuffix("/"+strings.Trim(prefix, "/"), "/")
 }
 
 func (s *Server) Start(addr string) error {
@@ -40,7 +120,18 @@ func (s *Server) Start(addr string) error {
 	mux.HandleFunc("/api/jobs", s.handleJobs)
 	mux.HandleFunc("/api/jobs/", s.handleJob)
 	mux.HandleFunc("/api/jobs/test/", s.handleTestJob)
+	mux.HandleFunc("/api/jobs/reorder", s.handleReorderJobs)
+	mux.HandleFunc("/api/jobs/validate", s.handleValidateJob)
+	mux.HandleFunc("/api/validate", s.handleValidate)
+	mux.HandleFunc("/api/history.csv", s.handleHistoryCSV)
 	mux.HandleFunc("/api/reminders/", s.handleReminder)
+	mux.HandleFunc("/api/parse-interval", s.handleParseInterval)
+	mux.HandleFunc("/api/scheduler/pause", s.handleSchedulerPause)
+	mux.HandleFunc("/api/scheduler/resume", s.handleSchedulerResume)
+	mux.HandleFunc("/api/scheduler/status", s.handleSchedulerStatus)
+	mux.HandleFunc("/api/stats", s.handleStats)
+	mux.HandleFunc("/api/version", s.handleVersion)
+	mux.HandleFunc("/metrics", s.handleMetrics)
 
 	// Static files - serve from web/static subdirectory
 	staticFS, err := fs.Sub(webFS, "web/static")
@@ -52,7 +143,14 @@ func (s *Server) Start(addr string) error {
 	// UI routes
 	mux.HandleFunc("/", s.handleIndex)
 
-	return http.ListenAndServe(addr, mux)
+	var handler http.Handler = mux
+	if s.basePath != "" {
+		prefixed := http.NewServeMux()
+		prefixed.Handle(s.basePath+"/", http.StripPrefix(s.basePath, mux))
+		handler = prefixed
+	}
+
+	return http.ListenAndServe(addr, handler)
 }
 
 func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
@@ -61,8 +159,15 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
+	if s.templateDir != "" {
+		if err := s.ReloadTemplates(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
 	jobs := s.config.GetAllJobs()
-	if err := s.templates.ExecuteTemplate(w, "index.html", jobs); err != nil {
+	if err := s.currentTemplates().ExecuteTemplate(w, "index.html", jobs); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -72,139 +177,985 @@ func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
 		jobs := s.config.GetAllJobs()
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(jobs); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		// Optionally filter by state (draft, enabled, disabled)
+		if status := r.URL.Query().Get("status"); status != "" {
+			filtered := []config.CronJob{}
+			for _, job := range jobs {
+				if job.State() == status {
+					filtered = append(filtered, job)
+				}
+			}
+			jobs = filtered
+		}
+
+		if err := writeNegotiated(w, r, jobs); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 		
 	case http.MethodPost:
+		if s.rejectReadOnly(w) {
+			return
+		}
+
 		var job config.CronJob
-		if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+		if err := decodeJob(r, &job); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
 			return
 		}
-		
-		if err := s.config.AddJob(job); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		if err := scheduler.CheckJob(s.config, &job); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// A re-enabled job no longer carries the reason it was disabled
+		if job.Enabled {
+			job.DisabledReason = ""
+		}
+
+		// A job without an ID gets a generated one, so retrying such a POST
+		// creates another job. With a client-supplied ID an existing job is
+		// replaced, or with ?on_conflict=reject the request fails with 409.
+		onConflict := r.URL.Query().Get("on_conflict")
+		if onConflict != "" && onConflict != "replace" && onConflict != "reject" {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid on_conflict %q, expected replace or reject", onConflict))
+			return
+		}
+		if job.ID == "" {
+			job.ID = config.NewUUID()
+		}
+		previous, _ := s.config.GetJob(job.ID)
+		job.KeepReminderRunState(s.storedReminders(job.ID))
+
+		created := true
+		if err := s.config.CreateJob(job); errors.Is(err, config.ErrJobExists) {
+			if onConflict == "reject" {
+				writeError(w, http.StatusConflict, err.Error())
+				return
+			}
+			created = false
+			if err := s.config.AddJob(job); err != nil {
+				writeError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+		} else if errors.Is(err, config.ErrJobLimit) {
+			writeError(w, http.StatusForbidden, err.Error())
+			return
+		} else if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 		
 		if err := s.config.Save(); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			// Memory goes back to what is on disk and scheduled
+			s.config.RestoreJob(job.ID, previous)
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 		
 		if err := s.scheduler.AddJob(job); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 		
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(job); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		status := http.StatusOK
+		if created {
+			status = http.StatusCreated
+			w.Header().Set("Location", s.basePath+"/api/jobs/"+url.PathEscape(job.ID))
+		}
+		if err := writeNegotiatedStatus(w, r, status, job); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 
+	case http.MethodDelete:
+		s.handleBulkDelete(w, r)
+
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// handleBulkDelete deletes every job matching the tag and/or name prefix
+// filters, saving the config once at the end
+func (s *Server) handleBulkDelete(w http.ResponseWriter, r *http.Request) {
+	if s.rejectReadOnly(w) {
+		return
+	}
+
+	query := r.URL.Query()
+	tag := query.Get("tag")
+	prefix := query.Get("prefix")
+
+	if tag == "" && prefix == "" {
+		writeError(w, http.StatusBadRequest, "At least one filter (tag or prefix) is required")
+		return
+	}
+
+	if query.Get("confirm") != "true" {
+		writeError(w, http.StatusBadRequest, "Bulk delete requires confirm=true")
+		return
+	}
+
+	deleted := s.config.DeleteJobsMatching(func(job config.CronJob) bool {
+		if tag != "" && !job.HasTag(tag) {
+			return false
+		}
+		if prefix != "" && !strings.HasPrefix(job.Name, prefix) {
+			return false
+		}
+		return true
+	})
+
+	if len(deleted) > 0 {
+		if err := s.config.Save(); err != nil {
+			// The jobs are still on disk and scheduled, so keep them
+			s.config.RestoreJobs(deleted)
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	ids := make([]string, 0, len(deleted))
+	for _, job := range deleted {
+		if err := s.scheduler.RemoveJob(job.ID); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		ids = append(ids, job.ID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"deleted": len(ids),
+		"ids":     ids,
+	}); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
 	}
 }
 
 func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	// Path format: /api/jobs/{jobID} or /api/jobs/{jobID}/{action}
+	pathParts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/jobs/"), "/"), "/")
+	if len(pathParts) == 2 {
+		s.handleJobAction(w, r, pathParts[0], pathParts[1])
+		return
+	}
+
 	jobID := path.Base(r.URL.Path)
 
 	switch r.Method {
 	case http.MethodGet:
 		job, err := s.config.GetJob(jobID)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			writeError(w, http.StatusNotFound, err.Error())
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(job); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		if err := writeNegotiated(w, r, job); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 		
 	case http.MethodPut:
+		if s.rejectReadOnly(w) {
+			return
+		}
+
 		var job config.CronJob
-		if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+		if err := decodeJob(r, &job); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
 			return
 		}
 		
 		if job.ID != jobID {
-			http.Error(w, "Job ID mismatch", http.StatusBadRequest)
+			writeError(w, http.StatusBadRequest, "Job ID mismatch")
+			return
+		}
+
+		if err := scheduler.CheckJob(s.config, &job); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
 			return
 		}
+		previous, _ := s.config.GetJob(job.ID)
+		job.KeepReminderRunState(s.storedReminders(job.ID))
+
+		// A re-enabled job no longer carries the reason it was disabled
+		if job.Enabled {
+			job.DisabledReason = ""
+		}
 		
-		if err := s.config.AddJob(job); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		if err := s.config.AddJob(job); errors.Is(err, config.ErrJobLimit) {
+			writeError(w, http.StatusForbidden, err.Error())
+			return
+		} else if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 		
 		if err := s.config.Save(); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			s.config.RestoreJob(job.ID, previous)
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 		
 		if err := s.scheduler.AddJob(job); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 		
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(job); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		if err := writeNegotiated(w, r, job); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 
 	case http.MethodDelete:
+		if s.rejectReadOnly(w) {
+			return
+		}
+
+		job, err := s.config.GetJob(jobID)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
 		if err := s.config.DeleteJob(jobID); err != nil {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			writeError(w, http.StatusNotFound, err.Error())
 			return
 		}
 		
 		if err := s.config.Save(); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			s.config.RestoreJobs([]config.CronJob{*job})
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 		
 		if err := s.scheduler.RemoveJob(jobID); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 
 		w.WriteHeader(http.StatusNoContent)
 		
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// handleJobAction dispatches /api/jobs/{jobID}/{action} requests
+func (s *Server) handleJobAction(w http.ResponseWriter, r *http.Request, jobID, action string) {
+	switch action {
+	case "disable-reminders-in-past":
+		s.handleRemovePastReminders(w, r, jobID)
+	case "explain":
+		s.handleExplainSchedule(w, r, jobID)
+	case "history":
+		s.handleJobHistory(w, r, jobID)
+	case "history.csv":
+		s.handleJobHistoryCSV(w, r, jobID)
+	case "invoke":
+		s.handleInvokeJob(w, r, jobID)
+	case "output":
+		s.handleJobOutput(w, r, jobID)
+	case "override-schedule":
+		s.handleScheduleOverride(w, r, jobID)
+	case "rerun-secondary":
+		s.handleRerunSecondary(w, r, jobID)
+	case "slo":
+		s.handleJobSLO(w, r, jobID)
+	case "variables":
+		s.handleJobVariables(w, r, jobID)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown job action %q", action))
+	}
+}
+
+// handleExplainSchedule describes a job's schedule in plain English
+func (s *Server) handleExplainSchedule(w http.ResponseWriter, r *http.Request, jobID string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	job, err := s.config.GetJob(jobID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if err := scheduler.ResolveSchedule(job); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if job.Schedule == "" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("job %s has no schedule", jobID))
+		return
+	}
+
+	explanation, err := scheduler.ExplainSchedule(job.Schedule)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(explanation); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// handleJobVariables reports the placeholders a job's templates reference
+// and which of them its selectors or the scheduler provide
+func (s *Server) handleJobVariables(w http.ResponseWriter, r *http.Request, jobID string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	job, err := s.config.GetJob(jobID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(scheduler.AnalyzeVariables(*job)); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// handleInvokeJob runs a job synchronously with the variables in the JSON
+// request body and returns the execution and primary response
+func (s *Server) handleInvokeJob(w http.ResponseWriter, r *http.Request, jobID string) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	vars := map[string]interface{}{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&vars); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid variables: %v", err))
+			return
+		}
+	}
+
+	result, err := s.scheduler.Invoke(jobID, vars)
+	if errors.Is(err, scheduler.ErrManualLimit) {
+		writeError(w, http.StatusTooManyRequests, err.Error())
+		return
+	} else if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// handleRerunSecondary re-runs a job's secondary webhook against its saved
+// output and waits for it
+func (s *Server) handleRerunSecondary(w http.ResponseWriter, r *http.Request, jobID string) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	result, err := s.scheduler.RerunSecondary(jobID)
+	if errors.Is(err, scheduler.ErrManualLimit) {
+		writeError(w, http.StatusTooManyRequests, err.Error())
+		return
+	} else if errors.Is(err, scheduler.ErrNoSecondary) || errors.Is(err, scheduler.ErrNoSavedOutput) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	} else if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// handleJobOutput returns a job's saved output, pretty-printed when the job
+// sets pretty_output or the request passes ?pretty=true
+func (s *Server) handleJobOutput(w http.ResponseWriter, r *http.Request, jobID string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	job, err := s.config.GetJob(jobID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	pretty := job.PrettyOutput
+	if value := r.URL.Query().Get("pretty"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid pretty value %q", value))
+			return
+		}
+		pretty = parsed
+	}
+
+	output, contentType, ok := s.scheduler.Output(jobID, pretty)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no saved output for job %s", jobID))
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	io.WriteString(w, output)
+}
+
+// handleScheduleOverride shows (GET), sets (POST) or ends (DELETE) a job's
+// temporary schedule override. POST takes {"schedule": "...", "ttl": "10m"}.
+func (s *Server) handleScheduleOverride(w http.ResponseWriter, r *http.Request, jobID string) {
+	switch r.Method {
+	case http.MethodGet:
+		override, ok := s.scheduler.Override(jobID)
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("no schedule override for job %s", jobID))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(override); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+	case http.MethodPost:
+		var req struct {
+			Schedule string `json:"schedule"`
+			TTL      string `json:"ttl"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ttl, err := time.ParseDuration(req.TTL)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid ttl %q: %v", req.TTL, err))
+			return
+		}
+
+		if _, err := s.config.GetJob(jobID); err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		override, err := s.scheduler.OverrideSchedule(jobID, req.Schedule, ttl)
+		if errors.Is(err, scheduler.ErrInvalidOverride) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		} else if errors.Is(err, config.ErrJobNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		} else if errors.Is(err, scheduler.ErrOverrideNotEnabled) || errors.Is(err, scheduler.ErrOverrideChained) {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		} else if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(override); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+	case http.MethodDelete:
+		cleared, err := s.scheduler.ClearScheduleOverride(jobID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if !cleared {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("no schedule override for job %s", jobID))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// handleJobHistory returns a job's recent executions, newest first
+func (s *Server) handleJobHistory(w http.ResponseWriter, r *http.Request, jobID string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if _, err := s.config.GetJob(jobID); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.scheduler.History(jobID)); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// handleJobHistoryCSV streams a job's executions as CSV
+func (s *Server) handleJobHistoryCSV(w http.ResponseWriter, r *http.Request, jobID string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if _, err := s.config.GetJob(jobID); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeHistoryCSV(w, jobID+"-history.csv", func(write func(scheduler.Execution) error) error {
+		for _, execution := range s.scheduler.History(jobID) {
+			if err := write(execution); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// handleHistoryCSV streams the executions of every job as CSV
+func (s *Server) handleHistoryCSV(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	writeHistoryCSV(w, "history.csv", func(write func(scheduler.Execution) error) error {
+		for _, job := range s.config.GetAllJobs() {
+			for _, execution := range s.scheduler.History(job.ID) {
+				if err := write(execution); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// writeHistoryCSV writes a CSV header and then one row per execution
+// produced by each, flushing as it goes so large histories are streamed
+func writeHistoryCSV(w http.ResponseWriter, filename string, each func(func(scheduler.Execution) error) error) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	flusher, _ := w.(http.Flusher)
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"job_id", "run_id", "timestamp", "duration_ms", "status", "error", "trigger"}); err != nil {
+		return
+	}
+
+	rows := 0
+	_ = each(func(e scheduler.Execution) error {
+		if err := cw.Write([]string{
+			csvText(e.JobID),
+			csvText(e.ID),
+			e.StartedAt.Format(time.RFC3339),
+			strconv.FormatFloat(e.DurationMs, 'f', -1, 64),
+			csvText(e.Status),
+			csvText(e.Error),
+			csvText(e.Trigger),
+		}); err != nil {
+			return err
+		}
+
+		rows++
+		if rows%100 == 0 {
+			cw.Flush()
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return cw.Error()
+	})
+
+	cw.Flush()
+}
+
+// csvText prefixes a text cell with a single quote when it starts with a
+// character spreadsheets read as the start of a formula, so a job ID or
+// error message opened in Excel or Sheets is shown rather than evaluated
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// handleRemovePastReminders deletes a job's past-due reminders from the config
+func (s *Server) handleRemovePastReminders(w http.ResponseWriter, r *http.Request, jobID string) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if s.rejectReadOnly(w) {
+		return
+	}
+
+	previous, err := s.config.GetJob(jobID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	removed, err := s.config.RemovePastReminders(jobID, time.Now())
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if removed > 0 {
+		if err := s.config.Save(); err != nil {
+			s.config.RestoreJob(jobID, previous)
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]int{"removed": removed}); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// handleValidateJob checks a job definition without saving it and returns
+// non-fatal template and schedule warnings
+func (s *Server) handleValidateJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var job config.CronJob
+	if err := decodeJob(r, &job); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := scheduler.CheckJob(s.config, &job); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	warnings := append(scheduler.TemplateWarnings(job), scheduler.ScheduleWarnings(job)...)
+	warnings = append(warnings, scheduler.OutputsFromWarnings(job, s.config.GetAllJobs())...)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string][]string{"warnings": warnings}); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// handleValidate validates a whole set of jobs, given as an array or as a
+// config document with a jobs key, and reports errors and warnings per job.
+// Nothing is created or scheduled.
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var document struct {
+		Jobs []config.CronJob `yaml:"jobs" json:"jobs"`
+	}
+	unmarshal := json.Unmarshal
+	if isYAMLMediaType(r.Header.Get("Content-Type")) {
+		unmarshal = yaml.Unmarshal
+	}
+	if err := unmarshal(body, &document); err != nil {
+		if err := unmarshal(body, &document.Jobs); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if err := writeNegotiated(w, r, scheduler.ValidateJobs(document.Jobs, s.config)); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// handleVersion reports the build version, commit, build date and Go version
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if err := writeNegotiated(w, r, version.Get()); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// handleReorderJobs persists a new job order from an ordered list of IDs
+func (s *Server) handleReorderJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if s.rejectReadOnly(w) {
+		return
+	}
+
+	var req struct {
+		IDs []string `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	previous := s.config.JobOrder()
+	if err := s.config.ReorderJobs(req.IDs); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.config.Save(); err != nil {
+		s.config.RestoreOrder(previous)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := writeNegotiated(w, r, s.config.GetAllJobs()); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
 	}
 }
 
 func (s *Server) handleTestJob(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	jobID := path.Base(r.URL.Path)
 
-	if err := s.scheduler.TestJob(jobID); err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+	if err := s.scheduler.TestJob(jobID); errors.Is(err, scheduler.ErrManualLimit) {
+		writeError(w, http.StatusTooManyRequests, err.Error())
+		return
+	} else if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// handleParseInterval previews the cron expression derived from a
+// human-friendly interval without saving anything
+func (s *Server) handleParseInterval(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req struct {
+		Interval string `json:"interval"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	schedule, err := scheduler.ParseInterval(req.Interval)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{
+		"interval": req.Interval,
+		"schedule": schedule,
+	}); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+func (s *Server) handleSchedulerPause(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	s.scheduler.PauseAll()
+	s.writeSchedulerStatus(w)
+}
+
+func (s *Server) handleSchedulerResume(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	s.scheduler.ResumeAll()
+	s.writeSchedulerStatus(w)
+}
+
+// handleStats returns aggregate job and execution statistics
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.scheduler.Stats()); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// handleJobSLO reports a job's success ratio over the SLO window, or over
+// ?window= when given
+func (s *Server) handleJobSLO(w http.ResponseWriter, r *http.Request, jobID string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if _, err := s.config.GetJob(jobID); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	window := s.scheduler.SLOWindow()
+	if value := r.URL.Query().Get("window"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid window %q: use a positive duration like 1h", value))
+			return
+		}
+		window = parsed
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.scheduler.SLO(jobID, window)); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// handleMetrics exposes per-job SLO data over the SLO window in the
+// Prometheus text format. Jobs without decided runs in the window have no
+// success ratio sample.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	window := s.scheduler.SLOWindow()
+	slos := []scheduler.SLO{}
+	for _, job := range s.config.GetAllJobs() {
+		slos = append(slos, s.scheduler.SLO(job.ID, window))
+	}
+
+	var b strings.Builder
+	b.WriteString("# HELP cron_job_slo_success_ratio Share of the job's non-skipped runs in the SLO window that succeeded.\n")
+	b.WriteString("# TYPE cron_job_slo_success_ratio gauge\n")
+	for _, slo := range slos {
+		if slo.SuccessRatio != nil {
+			fmt.Fprintf(&b, "cron_job_slo_success_ratio{job=%s,window=%s} %g\n", promLabel(slo.JobID), promLabel(slo.Window), *slo.SuccessRatio)
+		}
+	}
+	b.WriteString("# HELP cron_job_slo_runs Runs of the job started in the SLO window, by status.\n")
+	b.WriteString("# TYPE cron_job_slo_runs gauge\n")
+	for _, slo := range slos {
+		for _, count := range []struct {
+			status string
+			runs   int
+		}{{scheduler.StatusSuccess, slo.Successes}, {scheduler.StatusFailure, slo.Failures}, {scheduler.StatusSkipped, slo.Skipped}} {
+			fmt.Fprintf(&b, "cron_job_slo_runs{job=%s,window=%s,status=%q} %d\n", promLabel(slo.JobID), promLabel(slo.Window), count.status, count.runs)
+		}
+	}
+	b.WriteString("# HELP cron_job_slo_failures Failed runs of the job started in the SLO window, by error kind.\n")
+	b.WriteString("# TYPE cron_job_slo_failures gauge\n")
+	for _, slo := range slos {
+		for _, kind := range scheduler.ErrorKinds {
+			fmt.Fprintf(&b, "cron_job_slo_failures{job=%s,window=%s,error_kind=%q} %d\n", promLabel(slo.JobID), promLabel(slo.Window), kind, slo.FailureKinds[kind])
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	io.WriteString(w, b.String())
+}
+
+// promLabel quotes a Prometheus label value, escaping backslashes, quotes
+// and newlines
+func promLabel(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	return `"` + value + `"`
+}
+
+func (s *Server) handleSchedulerStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	s.writeSchedulerStatus(w)
+}
+
+func (s *Server) writeSchedulerStatus(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.scheduler.Status()); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
 func (s *Server) handleReminder(w http.ResponseWriter, r *http.Request) {
-	// Path format: /api/reminders/{jobID}/{reminderID}
+	// Path format: /api/reminders/{jobID}/{reminderID}[/retry]
 	pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(pathParts) == 5 && pathParts[4] == "retry" {
+		s.handleRetryReminder(w, r, pathParts[2], pathParts[3])
+		return
+	}
 	if len(pathParts) != 4 {
-		http.Error(w, "Invalid path", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "Invalid path")
 		return
 	}
 
@@ -213,10 +1164,14 @@ func (s *Server) handleReminder(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case http.MethodDelete:
+		if s.rejectReadOnly(w) {
+			return
+		}
+
 		// Get the job
 		job, err := s.config.GetJob(jobID)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			writeError(w, http.StatusNotFound, err.Error())
 			return
 		}
 
@@ -232,54 +1187,64 @@ func (s *Server) handleReminder(w http.ResponseWriter, r *http.Request) {
 		}
 
 		if !reminderFound {
-			http.Error(w, "Reminder not found", http.StatusNotFound)
+			writeError(w, http.StatusNotFound, "Reminder not found")
 			return
 		}
 
 		// Update the job with the new reminders list
+		previous := *job
 		job.Reminders = updatedReminders
 
 		// Save the updated job
 		if err := s.config.AddJob(*job); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 
 		if err := s.config.Save(); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			s.config.RestoreJob(jobID, &previous)
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 
 		// Update the scheduler
 		if err := s.scheduler.AddJob(*job); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 
 		w.WriteHeader(http.StatusNoContent)
 
 	case http.MethodPut:
+		if s.rejectReadOnly(w) {
+			return
+		}
+
 		// Get the job
 		job, err := s.config.GetJob(jobID)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			writeError(w, http.StatusNotFound, err.Error())
 			return
 		}
 
 		// Parse the updated reminder from request body
 		var updatedReminder config.Reminder
 		if err := json.NewDecoder(r.Body).Decode(&updatedReminder); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeError(w, http.StatusBadRequest, err.Error())
 			return
 		}
 
 		// Validate that the reminder ID matches
 		if updatedReminder.ID != reminderID {
-			http.Error(w, "Reminder ID mismatch", http.StatusBadRequest)
+			writeError(w, http.StatusBadRequest, "Reminder ID mismatch")
 			return
 		}
 
-		// Find and update the reminder
+		// Find and update the reminder, on a copy so a rejected edit leaves
+		// the stored job alone
+		previous := *job
+		stored := job.Reminders
+		job.Reminders = append([]config.Reminder(nil), job.Reminders...)
 		reminderFound := false
 		for i, reminder := range job.Reminders {
 			if reminder.ID == reminderID {
@@ -290,34 +1255,177 @@ func (s *Server) handleReminder(w http.ResponseWriter, r *http.Request) {
 		}
 
 		if !reminderFound {
-			http.Error(w, "Reminder not found", http.StatusNotFound)
+			writeError(w, http.StatusNotFound, "Reminder not found")
+			return
+		}
+
+		// The edited reminder goes through the same checks as a saved job
+		if err := scheduler.CheckJob(s.config, job); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
 			return
 		}
+		job.KeepReminderRunState(stored)
 
 		// Save the updated job
 		if err := s.config.AddJob(*job); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 
 		if err := s.config.Save(); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			s.config.RestoreJob(jobID, &previous)
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 
 		// Update the scheduler
 		if err := s.scheduler.AddJob(*job); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(updatedReminder); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
 
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// storedReminders returns the reminders of the stored job with the given
+// ID, or nil when there is none
+func (s *Server) storedReminders(jobID string) []config.Reminder {
+	job, err := s.config.GetJob(jobID)
+	if err != nil {
+		return nil
+	}
+	return job.Reminders
+}
+
+// handleRetryReminder runs a failed reminder again and returns the run
+func (s *Server) handleRetryReminder(w http.ResponseWriter, r *http.Request, jobID, reminderID string) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if s.rejectReadOnly(w) {
+		return
+	}
+
+	execution, err := s.scheduler.RetryReminder(jobID, reminderID)
+	if errors.Is(err, scheduler.ErrManualLimit) {
+		writeError(w, http.StatusTooManyRequests, err.Error())
+		return
+	} else if errors.Is(err, scheduler.ErrReminderNotFailed) || errors.Is(err, scheduler.ErrReminderRetrying) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	} else if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(execution); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+}
+
+// yamlMediaTypes are the media types treated as YAML in Accept and
+// Content-Type headers
+var yamlMediaTypes = map[string]bool{
+	"application/yaml":   true,
+	"application/x-yaml": true,
+	"text/yaml":          true,
+	"text/x-yaml":        true,
+}
+
+func isYAMLMediaType(value string) bool {
+	mediaType, _, err := mime.ParseMediaType(value)
+	return err == nil && yamlMediaTypes[mediaType]
+}
+
+// acceptsYAML reports whether the Accept header prefers YAML over JSON.
+// The first YAML or JSON media type listed wins; JSON is the default.
+func acceptsYAML(r *http.Request) bool {
+	for _, value := range strings.Split(r.Header.Get("Accept"), ",") {
+		if isYAMLMediaType(value) {
+			return true
+		}
+		if mediaType, _, err := mime.ParseMediaType(value); err == nil && mediaType == "application/json" {
+			return false
+		}
+	}
+	return false
+}
+
+// decodeJob decodes a job from the request body as YAML when the
+// Content-Type says so, otherwise as JSON
+func decodeJob(r *http.Request, job *config.CronJob) error {
+	if isYAMLMediaType(r.Header.Get("Content-Type")) {
+		return yaml.NewDecoder(r.Body).Decode(job)
+	}
+	return json.NewDecoder(r.Body).Decode(job)
+}
+
+// writeNegotiated encodes v as YAML when the client accepts it, otherwise
+// as JSON
+func writeNegotiated(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	return writeNegotiatedStatus(w, r, http.StatusOK, v)
+}
+
+// writeNegotiatedStatus is writeNegotiated with an explicit status code
+func writeNegotiatedStatus(w http.ResponseWriter, r *http.Request, status int, v interface{}) error {
+	if acceptsYAML(r) {
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		w.WriteHeader(status)
+		_, err = w.Write(data)
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// writeError writes a JSON error envelope of the form
+// {"error":{"code":"not_found","message":"..."}} with the given status
+func writeError(w http.ResponseWriter, status int, message string) {
+	code := strings.ToLower(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+	if code == "" {
+		code = "error"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
+
+// rejectReadOnly answers 409 Conflict when the config is read-only, so
+// mutating handlers can refuse before changing anything in memory
+func (s *Server) rejectReadOnly(w http.ResponseWriter) bool {
+	if !s.config.IsReadOnly() {
+		return false
 	}
-}
\ No newline at end of file
+
+	writeError(w, http.StatusConflict, "Configuration is read-only; changes are not accepted")
+	return true
+}
@@ -0,0 +1,273 @@
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"cron-microservice/internal/config"
+	"cron-microservice/internal/scheduler"
+)
+
+// newTestServer returns a server over an empty config saved to a
+// temporary directory
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	cfg := config.New(filepath.Join(t.TempDir(), "config.yaml"))
+	return New(cfg, scheduler.New(cfg))
+}
+
+// postJob posts a job to /api/jobs with the given query string
+func postJob(s *Server, query, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/api/jobs"+query, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	s.handleJobs(w, r)
+	return w
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestPostJobWithoutID(t *testing.T) {
+	s := newTestServer(t)
+	const body = `{"name": "job", "schedule": "@every 1h", "primary": {"url": "http://example.com", "method": "POST"}}`
+
+	ids := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		w := postJob(s, "", body)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want 201: %s", w.Code, w.Body)
+		}
+		var job config.CronJob
+		if err := json.Unmarshal(w.Body.Bytes(), &job); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if !uuidPattern.MatchString(job.ID) {
+			t.Errorf("generated id %q is not a UUID", job.ID)
+		}
+		if location := w.Header().Get("Location"); location != "/api/jobs/"+job.ID {
+			t.Errorf("Location = %q, want /api/jobs/%s", location, job.ID)
+		}
+		ids[job.ID] = true
+	}
+	if len(ids) != 2 || len(s.config.GetAllJobs()) != 2 {
+		t.Errorf("two POSTs without an id created %d job(s)", len(s.config.GetAllJobs()))
+	}
+}
+
+func TestPostJobOnConflict(t *testing.T) {
+	job := func(name string) string {
+		return `{"id": "job", "name": "` + name + `", "schedule": "@every 1h", "primary": {"url": "http://example.com", "method": "POST"}}`
+	}
+
+	for _, tc := range []struct {
+		query    string
+		wantCode int
+		wantName string
+	}{
+		{"", http.StatusOK, "second"},
+		{"?on_conflict=replace", http.StatusOK, "second"},
+		{"?on_conflict=reject", http.StatusConflict, "first"},
+		{"?on_conflict=merge", http.StatusBadRequest, "first"},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			s := newTestServer(t)
+			if w := postJob(s, "", job("first")); w.Code != http.StatusCreated {
+				t.Fatalf("first POST: status = %d, want 201: %s", w.Code, w.Body)
+			}
+
+			w := postJob(s, tc.query, job("second"))
+			if w.Code != tc.wantCode {
+				t.Errorf("second POST: status = %d, want %d: %s", w.Code, tc.wantCode, w.Body)
+			}
+			if w.Code == http.StatusOK && w.Header().Get("Location") != "" {
+				t.Errorf("replacing POST set Location %q", w.Header().Get("Location"))
+			}
+
+			stored, err := s.config.GetJob("job")
+			if err != nil {
+				t.Fatalf("GetJob: %v", err)
+			}
+			if stored.Name != tc.wantName {
+				t.Errorf("stored name = %q, want %q", stored.Name, tc.wantName)
+			}
+		})
+	}
+}
+
+func TestPostJobWithDuplicateReminderIDs(t *testing.T) {
+	s := newTestServer(t)
+	at := time.Now().Add(time.Hour).Format(time.RFC3339)
+	body := `{"id": "job", "name": "job", "schedule": "@every 1h", "primary": {"url": "http://example.com", "method": "POST"}, ` +
+		`"reminders": [{"id": "r1", "text": "a", "datetime": "` + at + `"}, {"id": "r1", "text": "b", "datetime": "` + at + `"}]}`
+
+	w := postJob(s, "", body)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400: %s", w.Code, w.Body)
+	}
+	if !strings.Contains(w.Body.String(), "r1") {
+		t.Errorf("error does not name the duplicate id: %s", w.Body)
+	}
+	if _, err := s.config.GetJob("job"); err == nil {
+		t.Error("a job with duplicate reminder ids was stored")
+	}
+}
+
+func TestDeleteKeepsJobsWhenSaveFails(t *testing.T) {
+	// The config file's directory doesn't exist, so every save fails
+	cfg := config.New(filepath.Join(t.TempDir(), "missing", "config.yaml"))
+	for _, id := range []string{"a", "b"} {
+		job := config.CronJob{ID: id, Name: id, Schedule: "@every 1h", Tags: []string{"batch"}}
+		if err := cfg.AddJob(job); err != nil {
+			t.Fatalf("AddJob(%s): %v", id, err)
+		}
+	}
+	s := New(cfg, scheduler.New(cfg))
+
+	for _, target := range []string{"/api/jobs/a", "/api/jobs?tag=batch&confirm=true"} {
+		r := httptest.NewRequest(http.MethodDelete, target, nil)
+		w := httptest.NewRecorder()
+		if strings.HasPrefix(target, "/api/jobs/") {
+			s.handleJob(w, r)
+		} else {
+			s.handleJobs(w, r)
+		}
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("DELETE %s: status = %d, want 500: %s", target, w.Code, w.Body)
+		}
+		if got := len(cfg.GetAllJobs()); got != 2 {
+			t.Errorf("DELETE %s: %d jobs left in the config after a failed save, want 2", target, got)
+		}
+	}
+}
+
+func TestEditsRollBackWhenSaveFails(t *testing.T) {
+	// The config file's directory doesn't exist, so every save fails
+	cfg := config.New(filepath.Join(t.TempDir(), "missing", "config.yaml"))
+	reminder := config.Reminder{ID: "r1", Text: "hi", Datetime: time.Now().Add(time.Hour)}
+	past := config.Reminder{ID: "old", Text: "hi", Datetime: time.Now().Add(-time.Hour)}
+	for _, job := range []config.CronJob{
+		{ID: "a", Name: "a", Schedule: "@every 1h", Reminders: []config.Reminder{reminder}},
+		{ID: "b", Name: "b", Schedule: "@every 1h", Reminders: []config.Reminder{past}},
+	} {
+		if err := cfg.AddJob(job); err != nil {
+			t.Fatalf("AddJob(%s): %v", job.ID, err)
+		}
+	}
+	s := New(cfg, scheduler.New(cfg))
+	const webhook = `"primary": {"url": "http://example.com", "method": "POST"}`
+
+	for _, tc := range []struct {
+		method, target, body string
+		handler              http.HandlerFunc
+	}{
+		{http.MethodPost, "/api/jobs", `{"id": "c", "name": "c", "schedule": "@every 1h", ` + webhook + `}`, s.handleJobs},
+		{http.MethodPost, "/api/jobs", `{"id": "a", "name": "renamed", "schedule": "@every 1h", ` + webhook + `}`, s.handleJobs},
+		{http.MethodPut, "/api/jobs/a", `{"id": "a", "name": "replaced", "schedule": "@every 1h", ` + webhook + `}`, s.handleJob},
+		{http.MethodPost, "/api/jobs/reorder", `{"ids": ["b", "a"]}`, s.handleReorderJobs},
+		{http.MethodPost, "/api/jobs/b/disable-reminders-in-past", "", s.handleJob},
+		{http.MethodPut, "/api/reminders/a/r1", `{"id": "r1", "text": "edited", "datetime": "` + reminder.Datetime.Format(time.RFC3339) + `"}`, s.handleReminder},
+		{http.MethodDelete, "/api/reminders/a/r1", "", s.handleReminder},
+	} {
+		before := cfg.GetAllJobs()
+		r := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+		w := httptest.NewRecorder()
+		tc.handler(w, r)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("%s %s: status = %d, want 500: %s", tc.method, tc.target, w.Code, w.Body)
+		}
+		if after := cfg.GetAllJobs(); !reflect.DeepEqual(after, before) {
+			t.Errorf("%s %s: jobs changed in memory after a failed save:\n got %+v\nwant %+v", tc.method, tc.target, after, before)
+		}
+	}
+}
+
+func TestHistoryCSVEscapesFormulas(t *testing.T) {
+	execution := scheduler.Execution{
+		ID:      "run",
+		JobID:   "=HYPERLINK(\"http://example.com\")",
+		Trigger: "manual",
+		Status:  "failure",
+		Error:   "@SUM(1+1)",
+	}
+	w := httptest.NewRecorder()
+	writeHistoryCSV(w, "history.csv", func(write func(scheduler.Execution) error) error {
+		return write(execution)
+	})
+
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("read CSV: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want a header and one execution", len(rows))
+	}
+	row := rows[1]
+	for i, want := range map[int]string{0: "'" + execution.JobID, 1: "run", 4: "failure", 5: "'@SUM(1+1)", 6: "manual"} {
+		if row[i] != want {
+			t.Errorf("column %s = %q, want %q", rows[0][i], row[i], want)
+		}
+	}
+}
+
+func TestCSVText(t *testing.T) {
+	for value, want := range map[string]string{
+		"":        "",
+		"job":     "job",
+		"=1+1":    "'=1+1",
+		"+1":      "'+1",
+		"-1":      "'-1",
+		"@cmd":    "'@cmd",
+		"\t=1":    "'\t=1",
+		"\r=1":    "'\r=1",
+		"a=1":     "a=1",
+		"'quoted": "'quoted",
+	} {
+		if got := csvText(value); got != want {
+			t.Errorf("csvText(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestOverrideScheduleStatus(t *testing.T) {
+	s := newTestServer(t)
+	for _, job := range []config.CronJob{
+		{ID: "enabled", Name: "enabled", Schedule: "@every 1h", Enabled: true},
+		{ID: "disabled", Name: "disabled", Schedule: "@every 1h"},
+		{ID: "chained", Name: "chained", AfterJob: "enabled", Enabled: true},
+	} {
+		if err := s.config.AddJob(job); err != nil {
+			t.Fatalf("AddJob(%s): %v", job.ID, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		jobID    string
+		body     string
+		wantCode int
+	}{
+		{"valid", "enabled", `{"schedule": "* * * * *", "ttl": "1h"}`, http.StatusOK},
+		{"unparsable ttl", "enabled", `{"schedule": "* * * * *", "ttl": "soon"}`, http.StatusBadRequest},
+		{"negative ttl", "enabled", `{"schedule": "* * * * *", "ttl": "-1m"}`, http.StatusBadRequest},
+		{"bad schedule", "enabled", `{"schedule": "bogus", "ttl": "1h"}`, http.StatusBadRequest},
+		{"missing job", "missing", `{"schedule": "* * * * *", "ttl": "1h"}`, http.StatusNotFound},
+		{"disabled job", "disabled", `{"schedule": "* * * * *", "ttl": "1h"}`, http.StatusConflict},
+		{"chained job", "chained", `{"schedule": "* * * * *", "ttl": "1h"}`, http.StatusConflict},
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/api/jobs/"+tc.jobID+"/override-schedule", strings.NewReader(tc.body))
+		w := httptest.NewRecorder()
+		s.handleScheduleOverride(w, r, tc.jobID)
+		if w.Code != tc.wantCode {
+			t.Errorf("%s: status = %d, want %d: %s", tc.name, w.Code, tc.wantCode, w.Body)
+		}
+	}
+}
@@ -1,3 +1,6 @@
+// Prefix for every route when served under a base path, set by the page
+const BASE_PATH = window.BASE_PATH || '';
+
 // Theme management
 function initTheme() {
     const savedTheme = localStorage.getItem('theme') || 'light';
@@ -28,7 +31,7 @@ function showAddJobModal() {
 }
 
 function editJob(jobId) {
-    fetch(`/api/jobs/${jobId}`)
+    fetch(`${BASE_PATH}/api/jobs/${jobId}`)
         .then(response => response.json())
         .then(job => {
             document.getElementById('modal-title').textContent = 'Edit Cron Job';
@@ -141,7 +144,7 @@ function saveJob(event) {
         };
     }
     
-    const url = isNew ? '/api/jobs' : `/api/jobs/${jobId}`;
+    const url = isNew ? `${BASE_PATH}/api/jobs` : `${BASE_PATH}/api/jobs/${jobId}`;
     const method = isNew ? 'POST' : 'PUT';
     
     fetch(url, {
@@ -172,7 +175,7 @@ function deleteJob(jobId) {
         return;
     }
     
-    fetch(`/api/jobs/${jobId}`, {
+    fetch(`${BASE_PATH}/api/jobs/${jobId}`, {
         method: 'DELETE'
     })
     .then(response => {
@@ -194,7 +197,7 @@ function testJob(jobId) {
     button.textContent = 'Testing...';
     button.disabled = true;
     
-    fetch(`/api/jobs/test/${jobId}`, {
+    fetch(`${BASE_PATH}/api/jobs/test/${jobId}`, {
         method: 'POST'
     })
     .then(response => {
@@ -304,7 +307,7 @@ function saveReminder(event) {
 
 function addNewReminder(jobId, reminder) {
     // Get current job data
-    fetch(`/api/jobs/${jobId}`)
+    fetch(`${BASE_PATH}/api/jobs/${jobId}`)
         .then(response => response.json())
         .then(job => {
             // Add reminder to job
@@ -314,7 +317,7 @@ function addNewReminder(jobId, reminder) {
             job.reminders.push(reminder);
 
             // Update job
-            return fetch(`/api/jobs/${jobId}`, {
+            return fetch(`${BASE_PATH}/api/jobs/${jobId}`, {
                 method: 'PUT',
                 headers: {
                     'Content-Type': 'application/json'
@@ -339,7 +342,7 @@ function addNewReminder(jobId, reminder) {
 }
 
 function updateExistingReminder(jobId, updatedReminder) {
-    fetch(`/api/reminders/${jobId}/${updatedReminder.id}`, {
+    fetch(`${BASE_PATH}/api/reminders/${jobId}/${updatedReminder.id}`, {
         method: 'PUT',
         headers: {
             'Content-Type': 'application/json'
@@ -367,7 +370,7 @@ function deleteReminder(reminderId, jobId) {
         return;
     }
 
-    fetch(`/api/reminders/${jobId}/${reminderId}`, {
+    fetch(`${BASE_PATH}/api/reminders/${jobId}/${reminderId}`, {
         method: 'DELETE'
     })
     .then(response => {
@@ -428,7 +431,7 @@ function updateReminder(event) {
         datetime: formattedDatetime
     };
 
-    fetch(`/api/reminders/${jobId}/${reminderId}`, {
+    fetch(`${BASE_PATH}/api/reminders/${jobId}/${reminderId}`, {
         method: 'PUT',
         headers: {
             'Content-Type': 'application/json'
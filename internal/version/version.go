@@ -0,0 +1,72 @@
+// Package version holds build information injected at link time via
+// -ldflags "-X cron-microservice/internal/version.Version=..."
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+// Version is the release version of the service
+var Version = "dev"
+
+// Commit and BuildDate are set at link time like Version. When they are
+// not, the commit and its time are read from the VCS information the Go
+// toolchain embeds.
+var (
+	Commit    = ""
+	BuildDate = ""
+)
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+}
+
+var (
+	infoOnce sync.Once
+	info     Info
+)
+
+// Get returns the build information. It is worked out once and cached.
+func Get() Info {
+	infoOnce.Do(func() {
+		info = Info{Version: Version, Commit: Commit, BuildDate: BuildDate, GoVersion: runtime.Version()}
+		if build, ok := debug.ReadBuildInfo(); ok {
+			for _, setting := range build.Settings {
+				switch {
+				case setting.Key == "vcs.revision" && info.Commit == "":
+					info.Commit = setting.Value
+				case setting.Key == "vcs.time" && info.BuildDate == "":
+					info.BuildDate = setting.Value
+				}
+			}
+		}
+		if info.Commit == "" {
+			info.Commit = "unknown"
+		}
+		if info.BuildDate == "" {
+			info.BuildDate = "unknown"
+		}
+	})
+	return info
+}
+
+// String renders the build information on one line, such as
+// "1.2.3 (commit 1a2b3c4, built 2024-05-01T10:00:00Z, go1.24.0)"
+func (i Info) String() string {
+	commit := i.Commit
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	return i.Version + " (commit " + commit + ", built " + i.BuildDate + ", " + i.GoVersion + ")"
+}
+
+// UserAgent returns the User-Agent sent on outgoing webhook requests
+func UserAgent() string {
+	return "cron-microservice/" + Version
+}